@@ -0,0 +1,115 @@
+// Package autoconfirm settles results the opponent never confirmed once the
+// league's confirmation window has passed.
+package autoconfirm
+
+import (
+	"errors"
+	"time"
+
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/store"
+)
+
+const DefaultWindow = 72 * time.Hour
+
+type Result struct {
+	Confirmed  int
+	Escalated  int
+	Friendlies int
+}
+
+// Enabled reports whether the league settles unconfirmed results at all.
+func Enabled(league model.League) bool {
+	return league.ConfirmationHours > 0
+}
+
+func Window(league model.League) time.Duration {
+	return time.Duration(league.ConfirmationHours) * time.Hour
+}
+
+func Deadline(league model.League, match model.Match) time.Time {
+	return match.ReportTime().Add(Window(league))
+}
+
+func Run(st store.Store, now time.Time) (Result, error) {
+	result := Result{}
+	var errs []error
+	for _, league := range st.ListLeagues() {
+		if !Enabled(league) {
+			continue
+		}
+		for _, match := range st.ListMatches(league.ID) {
+			if match.Status != model.MatchPending || now.Before(Deadline(league, match)) {
+				continue
+			}
+			escalate := league.AutoConfirmAction == model.AutoConfirmEscalate
+			if err := settleMatch(st, match, escalate, now); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if escalate {
+				result.Escalated++
+			} else {
+				result.Confirmed++
+			}
+		}
+	}
+	for _, match := range st.ListFriendlyMatches() {
+		if match.Status != model.MatchPending || now.Before(match.CreatedAt.Add(DefaultWindow)) {
+			continue
+		}
+		match.Status = model.MatchConfirmed
+		match.AutoConfirmed = true
+		if err := st.UpdateFriendlyMatch(match); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result.Friendlies++
+	}
+	return result, errors.Join(errs...)
+}
+
+func settleMatch(st store.Store, match model.Match, escalate bool, now time.Time) error {
+	if len(st.ListMatchRevisions(match.ID)) == 0 {
+		if _, err := st.CreateMatchRevision(revision(match, model.RevisionReported, match.ReportedBy, "Zgłoszenie wyniku", match.ReportTime())); err != nil {
+			return err
+		}
+	}
+	action := model.RevisionAutoConfirmed
+	reason := "Brak potwierdzenia w terminie – wynik potwierdzony automatycznie"
+	if escalate {
+		match.Status = model.MatchDisputed
+		match.Dispute = &model.MatchDispute{
+			RaisedBy:   model.DisputeRaisedBySystem,
+			Sets:       match.Sets,
+			Outcome:    match.Outcome,
+			ConcededBy: match.ConcededBy,
+			Escalated:  true,
+			CreatedAt:  now,
+		}
+		action = model.RevisionAutoEscalated
+		reason = "Brak potwierdzenia w terminie – wynik przekazany administratorom"
+	} else {
+		match.Status = model.MatchConfirmed
+		match.AutoConfirmed = true
+	}
+	if err := st.UpdateMatch(match); err != nil {
+		return err
+	}
+	_, err := st.CreateMatchRevision(revision(match, action, "", reason, now))
+	return err
+}
+
+func revision(match model.Match, action model.MatchRevisionAction, changedBy string, reason string, at time.Time) model.MatchRevision {
+	return model.MatchRevision{
+		MatchID:    match.ID,
+		Action:     action,
+		Sets:       append([]model.SetScore{}, match.Sets...),
+		Outcome:    match.Outcome,
+		ConcededBy: match.ConcededBy,
+		Status:     match.Status,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+		CreatedAt:  at,
+	}
+}
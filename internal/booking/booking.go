@@ -0,0 +1,178 @@
+// Package booking reserves club courts. Handlers talk to a Provider, so a
+// club's own booking system can replace the built-in Local one without
+// touching them.
+package booking
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/store"
+)
+
+// SlotLength is how long one booking holds a court. Slots follow each other
+// from opening time until the last one that ends before closing.
+const SlotLength = 45 * time.Minute
+
+var (
+	ErrUnknownCourt = errors.New("klub nie ma takiego kortu")
+	ErrNoSlot       = errors.New("o tej godzinie nie zaczyna się żaden termin rezerwacji")
+	ErrConflict     = errors.New("kort jest już zarezerwowany w tym czasie")
+	ErrNotFound     = errors.New("nie znaleziono rezerwacji")
+)
+
+type Request struct {
+	CourtID string
+	UserID  string
+	MatchID string
+	Start   time.Time
+}
+
+// Slot is one court's time slot. Booking is nil while the slot is free.
+type Slot struct {
+	CourtID string
+	Start   time.Time
+	End     time.Time
+	Booking *model.CourtBooking
+}
+
+type Provider interface {
+	// Day lists every slot of the club's courts on the given day, booked or
+	// not, ordered by start time and then by court.
+	Day(club model.Club, day time.Time) ([]Slot, error)
+	Get(club model.Club, bookingID string) (model.CourtBooking, bool)
+	// ForMatch returns the live booking made for the match, if any.
+	ForMatch(club model.Club, matchID string) (model.CourtBooking, bool)
+	Book(club model.Club, request Request) (model.CourtBooking, error)
+	Cancel(club model.Club, bookingID string) error
+}
+
+// DaySlots lays out the free slots of a day from the club's opening hours.
+// Days without opening hours have none.
+func DaySlots(club model.Club, day time.Time) []Slot {
+	year, month, date := day.Date()
+	slots := []Slot{}
+	for _, hours := range club.OpeningHours {
+		if hours.Weekday != day.Weekday() {
+			continue
+		}
+		opens, err := time.Parse("15:04", hours.Opens)
+		if err != nil {
+			continue
+		}
+		closes, err := time.Parse("15:04", hours.Closes)
+		if err != nil {
+			continue
+		}
+		start := time.Date(year, month, date, opens.Hour(), opens.Minute(), 0, 0, day.Location())
+		end := time.Date(year, month, date, closes.Hour(), closes.Minute(), 0, 0, day.Location())
+		for ; !start.Add(SlotLength).After(end); start = start.Add(SlotLength) {
+			for _, court := range club.Courts {
+				slots = append(slots, Slot{CourtID: court.ID, Start: start, End: start.Add(SlotLength)})
+			}
+		}
+	}
+	return slots
+}
+
+// Conflict returns the live booking that overlaps the time on the court.
+func Conflict(bookings []model.CourtBooking, courtID string, start, end time.Time) (model.CourtBooking, bool) {
+	for _, booking := range bookings {
+		if booking.Active() && booking.CourtID == courtID && booking.Overlaps(start, end) {
+			return booking, true
+		}
+	}
+	return model.CourtBooking{}, false
+}
+
+// Local keeps bookings in the app's own store.
+type Local struct {
+	store store.Store
+	// mu makes the conflict check and the insert one step.
+	mu sync.Mutex
+}
+
+func NewLocal(st store.Store) *Local {
+	return &Local{store: st}
+}
+
+func (l *Local) Day(club model.Club, day time.Time) ([]Slot, error) {
+	slots := DaySlots(club, day)
+	bookings := l.store.ListCourtBookings(club.ID)
+	for i := range slots {
+		if booking, ok := Conflict(bookings, slots[i].CourtID, slots[i].Start, slots[i].End); ok {
+			slots[i].Booking = &booking
+		}
+	}
+	return slots, nil
+}
+
+func (l *Local) Get(club model.Club, bookingID string) (model.CourtBooking, bool) {
+	booking, ok := l.store.GetCourtBooking(bookingID)
+	if !ok || booking.ClubID != club.ID {
+		return model.CourtBooking{}, false
+	}
+	return booking, true
+}
+
+func (l *Local) ForMatch(club model.Club, matchID string) (model.CourtBooking, bool) {
+	if matchID == "" {
+		return model.CourtBooking{}, false
+	}
+	for _, booking := range l.store.ListCourtBookings(club.ID) {
+		if booking.Active() && booking.MatchID == matchID {
+			return booking, true
+		}
+	}
+	return model.CourtBooking{}, false
+}
+
+func (l *Local) Book(club model.Club, request Request) (model.CourtBooking, error) {
+	if _, ok := club.Court(request.CourtID); !ok {
+		return model.CourtBooking{}, ErrUnknownCourt
+	}
+	slot, ok := findSlot(DaySlots(club, request.Start), request.CourtID, request.Start)
+	if !ok {
+		return model.CourtBooking{}, ErrNoSlot
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, taken := Conflict(l.store.ListCourtBookings(club.ID), slot.CourtID, slot.Start, slot.End); taken {
+		return model.CourtBooking{}, ErrConflict
+	}
+	return l.store.CreateCourtBooking(model.CourtBooking{
+		ClubID:    club.ID,
+		CourtID:   slot.CourtID,
+		UserID:    request.UserID,
+		MatchID:   request.MatchID,
+		Start:     slot.Start,
+		End:       slot.End,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (l *Local) Cancel(club model.Club, bookingID string) error {
+	booking, ok := l.Get(club, bookingID)
+	if !ok {
+		return ErrNotFound
+	}
+	if !booking.Active() {
+		return nil
+	}
+	now := time.Now()
+	booking.CancelledAt = &now
+	return l.store.UpdateCourtBooking(booking)
+}
+
+func findSlot(slots []Slot, courtID string, start time.Time) (Slot, bool) {
+	for _, slot := range slots {
+		if slot.CourtID == courtID && slot.Start.Equal(start) {
+			return slot, true
+		}
+	}
+	return Slot{}, false
+}
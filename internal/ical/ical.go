@@ -0,0 +1,113 @@
+// Package ical writes iCalendar (RFC 5545) feeds that calendar apps can
+// subscribe to.
+package ical
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusTentative = "TENTATIVE"
+	StatusCancelled = "CANCELLED"
+)
+
+// Event is a single VEVENT. UID must stay the same across feed refreshes so
+// that calendar apps update the event instead of adding a copy.
+type Event struct {
+	UID         string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Summary     string
+	Location    string
+	Description string
+	Status      string
+	URL         string
+}
+
+type Calendar struct {
+	Name   string
+	Events []Event
+}
+
+// Write renders the calendar. Timed events are written in UTC; all-day
+// events use their own calendar date.
+func (c Calendar) Write(w io.Writer, now time.Time) error {
+	var b strings.Builder
+	line := func(name, value string) {
+		b.WriteString(fold(name + ":" + value))
+		b.WriteString("\r\n")
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//sqoush//liga squasha//PL")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		line("X-WR-CALNAME", escape(c.Name))
+	}
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, event := range c.Events {
+		line("BEGIN", "VEVENT")
+		line("UID", event.UID)
+		line("DTSTAMP", stamp)
+		if event.AllDay {
+			end := event.End
+			if !end.After(event.Start) {
+				end = event.Start.AddDate(0, 0, 1)
+			}
+			line("DTSTART;VALUE=DATE", event.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE", end.Format("20060102"))
+		} else {
+			line("DTSTART", event.Start.UTC().Format("20060102T150405Z"))
+			line("DTEND", event.End.UTC().Format("20060102T150405Z"))
+		}
+		line("SUMMARY", escape(event.Summary))
+		if event.Location != "" {
+			line("LOCATION", escape(event.Location))
+		}
+		if event.Description != "" {
+			line("DESCRIPTION", escape(event.Description))
+		}
+		if event.URL != "" {
+			line("URL", event.URL)
+		}
+		if event.Status != "" {
+			line("STATUS", event.Status)
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escape(value string) string {
+	return textEscaper.Replace(value)
+}
+
+// fold splits content lines longer than 75 octets, never inside a UTF-8
+// sequence.
+func fold(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	return b.String()
+}
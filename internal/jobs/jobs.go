@@ -0,0 +1,182 @@
+// Package jobs runs named periodic tasks. Locally a ticker drives the runner;
+// under Lambda EventBridge schedule invocations do. A Locker makes sure only
+// one instance executes a job at a time, and a RunLog shared by all instances
+// keeps a job from running again before its interval has passed.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context, now time.Time) error
+}
+
+// Locker grants exclusive execution of a named job. ok is false when another
+// instance already holds the lock.
+type Locker interface {
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+// RunLog remembers when each job last started. It is only consulted while
+// the job's lock is held.
+type RunLog interface {
+	LastRun(ctx context.Context, name string) (at time.Time, ok bool, err error)
+	RecordRun(ctx context.Context, name string, at time.Time) error
+}
+
+// dueSlack lets a job run slightly before its interval is up, so schedules
+// firing with a little jitter do not skip a whole period.
+const dueSlack = time.Minute
+
+type Runner struct {
+	jobs   []Job
+	locker Locker
+	runs   RunLog
+}
+
+func NewRunner(locker Locker, runs RunLog, jobs ...Job) *Runner {
+	if locker == nil {
+		locker = NewLocalLocker()
+	}
+	if runs == nil {
+		runs = NewLocalRunLog()
+	}
+	return &Runner{jobs: jobs, locker: locker, runs: runs}
+}
+
+func (r *Runner) Jobs() []Job {
+	return append([]Job{}, r.jobs...)
+}
+
+// Start runs due jobs on every tick until ctx is cancelled.
+func (r *Runner) Start(ctx context.Context, tick time.Duration) {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		if err := r.RunDue(ctx, time.Now()); err != nil {
+			log.Printf("jobs: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue runs the jobs whose interval has passed since their last run on any
+// instance.
+func (r *Runner) RunDue(ctx context.Context, now time.Time) error {
+	var errs []error
+	for _, job := range r.jobs {
+		if err := r.run(ctx, job, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunNamed runs the given jobs that are due; with no names it considers every
+// job. It is used by scheduled invocations, which may repeat or overlap.
+func (r *Runner) RunNamed(ctx context.Context, now time.Time, names ...string) error {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var errs []error
+	for _, job := range r.jobs {
+		if len(wanted) > 0 && !wanted[job.Name] {
+			continue
+		}
+		delete(wanted, job.Name)
+		if err := r.run(ctx, job, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for name := range wanted {
+		errs = append(errs, fmt.Errorf("unknown job %q", name))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *Runner) run(ctx context.Context, job Job, now time.Time) error {
+	unlock, ok, err := r.locker.TryLock(ctx, job.Name)
+	if err != nil {
+		return fmt.Errorf("%s: lock: %w", job.Name, err)
+	}
+	if !ok {
+		return nil
+	}
+	defer unlock()
+
+	last, ran, err := r.runs.LastRun(ctx, job.Name)
+	if err != nil {
+		return fmt.Errorf("%s: last run: %w", job.Name, err)
+	}
+	if ran && now.Sub(last) < job.Interval-dueSlack {
+		return nil
+	}
+	if err := r.runs.RecordRun(ctx, job.Name, now); err != nil {
+		return fmt.Errorf("%s: record run: %w", job.Name, err)
+	}
+	if err := job.Run(ctx, now); err != nil {
+		return fmt.Errorf("%s: %w", job.Name, err)
+	}
+	return nil
+}
+
+// LocalLocker only guards against overlapping runs inside one process.
+type LocalLocker struct {
+	mu     sync.Mutex
+	active map[string]bool
+}
+
+func NewLocalLocker() *LocalLocker {
+	return &LocalLocker{active: make(map[string]bool)}
+}
+
+func (l *LocalLocker) TryLock(_ context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active[name] {
+		return nil, false, nil
+	}
+	l.active[name] = true
+	return func() {
+		l.mu.Lock()
+		delete(l.active, name)
+		l.mu.Unlock()
+	}, true, nil
+}
+
+// LocalRunLog keeps run times in memory, so they are lost on restart.
+type LocalRunLog struct {
+	mu   sync.Mutex
+	last map[string]time.Time
+}
+
+func NewLocalRunLog() *LocalRunLog {
+	return &LocalRunLog{last: make(map[string]time.Time)}
+}
+
+func (l *LocalRunLog) LastRun(_ context.Context, name string) (time.Time, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	at, ok := l.last[name]
+	return at, ok, nil
+}
+
+func (l *LocalRunLog) RecordRun(_ context.Context, name string, at time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.last[name] = at
+	return nil
+}
@@ -0,0 +1,102 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"sqoush-app/internal/autoconfirm"
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/store"
+	"sqoush-app/internal/waitlist"
+)
+
+const (
+	LeagueStatusJobName = "league-status"
+	AutoConfirmJobName  = "auto-confirm"
+	WaitlistJobName     = "waitlist"
+)
+
+// FinishHook is called once a league has been moved to the finished status.
+type FinishHook func(league model.League) error
+
+func Default(st store.Store, onFinish FinishHook) []Job {
+	return []Job{
+		LeagueStatusJob(st, onFinish),
+		AutoConfirmJob(st),
+		WaitlistJob(st),
+	}
+}
+
+// LeagueStatusJob moves leagues forward from upcoming to active to finished as
+// their dates pass. A league ended early by an admin is never reopened.
+func LeagueStatusJob(st store.Store, onFinish FinishHook) Job {
+	return Job{
+		Name:     LeagueStatusJobName,
+		Interval: time.Hour,
+		Run: func(_ context.Context, now time.Time) error {
+			var errs []error
+			for _, league := range st.ListLeagues() {
+				status := model.LeagueStatusForDates(league.StartDate, league.EndDate, now)
+				if statusRank(status) <= statusRank(league.Status) {
+					continue
+				}
+				league.Status = status
+				if err := st.UpdateLeague(league); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				log.Printf("jobs: liga %s ma status %s", league.ID, status)
+				if status == model.LeagueStatusFinished && onFinish != nil {
+					if err := onFinish(league); err != nil {
+						errs = append(errs, err)
+					}
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+func AutoConfirmJob(st store.Store) Job {
+	return Job{
+		Name:     AutoConfirmJobName,
+		Interval: 15 * time.Minute,
+		Run: func(_ context.Context, now time.Time) error {
+			result, err := autoconfirm.Run(st, now)
+			if result.Confirmed+result.Escalated+result.Friendlies > 0 {
+				log.Printf("jobs: potwierdzono %d, przekazano %d, towarzyskie %d", result.Confirmed, result.Escalated, result.Friendlies)
+			}
+			return err
+		},
+	}
+}
+
+// WaitlistJob withdraws lapsed slot offers and passes the slots on to the
+// next waiting players.
+func WaitlistJob(st store.Store) Job {
+	return Job{
+		Name:     WaitlistJobName,
+		Interval: 15 * time.Minute,
+		Run: func(_ context.Context, now time.Time) error {
+			result, err := waitlist.Run(st, now)
+			if result.Offered+result.Expired > 0 {
+				log.Printf("jobs: zaproponowano %d miejsc, wygasło %d propozycji", result.Offered, result.Expired)
+			}
+			return err
+		},
+	}
+}
+
+func statusRank(status model.LeagueStatus) int {
+	switch status {
+	case model.LeagueStatusUpcoming:
+		return 1
+	case model.LeagueStatusActive:
+		return 2
+	case model.LeagueStatusFinished:
+		return 3
+	}
+	return 0
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"slices"
 	"strings"
 	"time"
 )
@@ -24,10 +25,15 @@ const (
 	MatchPending   MatchStatus = "pending"
 	MatchConfirmed MatchStatus = "confirmed"
 	MatchRejected  MatchStatus = "rejected"
+	MatchDisputed  MatchStatus = "disputed"
+	MatchVoided    MatchStatus = "voided"
 
 	JoinRequestPending  JoinRequestStatus = "pending"
 	JoinRequestApproved JoinRequestStatus = "approved"
 	JoinRequestRejected JoinRequestStatus = "rejected"
+	// JoinRequestWaitlisted requests were accepted into a full league and
+	// wait on its waitlist.
+	JoinRequestWaitlisted JoinRequestStatus = "waitlisted"
 )
 
 type User struct {
@@ -59,16 +65,357 @@ type League struct {
 	Name         string
 	Description  string
 	Location     string
+	ClubID       string
 	OwnerID      string
 	AdminRoles   map[string]LeagueAdminRole
 	PlayerIDs    []string
 	SetsPerMatch int
-	StartDate    time.Time
-	EndDate      *time.Time
-	Status       LeagueStatus
+	PointsPerSet int
+	Scoring      ScoringRules
+	TieBreakers  []TieBreakRule
+	// ConfirmationHours is how long the opponent has to confirm a reported
+	// result before AutoConfirmAction is applied. Zero leaves results pending
+	// until someone acts on them.
+	ConfirmationHours int
+	AutoConfirmAction AutoConfirmAction
+	StartDate         time.Time
+	EndDate           *time.Time
+	Status            LeagueStatus
+	Season            int
+	PreviousSeasonID  string
+	// Divisions split the league into tiers ordered by Level, 1 being the top.
+	// PromotionCount players move between adjacent divisions each season.
+	Divisions      []Division
+	PromotionCount int
+	// Doubles leagues are played in pairs; results count for the pair and
+	// for each partner individually.
+	Doubles bool
+	// Team leagues are played between club teams. Each fixture consists of
+	// RubbersPerFixture individual matches in the captains' order of play.
+	Competition       CompetitionType
+	RubbersPerFixture int
+	Teams             []Team
+	// Handicap leagues give the weaker player a head start in every set.
+	// Handicaps holds the admin-set value per player; players without an
+	// entry get the default for their skill level.
+	Handicap  bool
+	Handicaps map[string]int
+	// WithdrawalPolicy decides what happens to the results of a player who
+	// leaves the league. Withdrawals also records current suspensions.
+	WithdrawalPolicy WithdrawalPolicy
+	Withdrawals      []Withdrawal
+	// Visibility controls who can find and open the league. JoinCode, when
+	// set, lets anyone who knows it join without waiting for approval.
+	Visibility LeagueVisibility
+	JoinCode   string
+	// MaxPlayers caps the roster, 0 meaning no limit. Players who ask to join
+	// a full league wait in Waitlist in the order they were admitted.
+	MaxPlayers int
+	Waitlist   []WaitlistEntry
+	// ArchivedAt hides a deleted league from everyone but its members, who
+	// keep read-only access to its history.
+	ArchivedAt *time.Time
+	// OwnershipTransfer is the owner's pending nomination of another admin
+	// to take the league over.
+	OwnershipTransfer *OwnershipTransfer
+	// Permissions overrides DefaultRoleCapabilities for the roles it lists.
+	Permissions map[LeagueAdminRole][]Capability
+	CreatedAt   time.Time
+}
+
+type OwnershipTransfer struct {
+	ToID      string
+	ByID      string
+	CreatedAt time.Time
+}
+
+// WaitlistEntry is a player waiting for a free slot. OfferExpiresAt is set
+// while a slot is held for them; the offer lapses after that time.
+type WaitlistEntry struct {
+	UserID         string
+	CreatedAt      time.Time
+	OfferExpiresAt *time.Time
+}
+
+func (e WaitlistEntry) Offered() bool {
+	return e.OfferExpiresAt != nil
+}
+
+type LeagueVisibility string
+
+const (
+	VisibilityPublic   LeagueVisibility = "public"
+	VisibilityUnlisted LeagueVisibility = "unlisted"
+	VisibilityPrivate  LeagueVisibility = "private"
+)
+
+// OpenSlots returns how many players can still join, counting slots held
+// for waitlisted players. It is -1 for leagues without a limit.
+func (l League) OpenSlots() int {
+	if l.MaxPlayers <= 0 {
+		return -1
+	}
+	held := 0
+	for _, entry := range l.Waitlist {
+		if entry.Offered() {
+			held++
+		}
+	}
+	return max(l.MaxPlayers-len(l.PlayerIDs)-held, 0)
+}
+
+func (l League) WaitlistEntry(userID string) (WaitlistEntry, int, bool) {
+	for i, entry := range l.Waitlist {
+		if entry.UserID == userID {
+			return entry, i + 1, true
+		}
+	}
+	return WaitlistEntry{}, 0, false
+}
+
+func (l League) RoleCapabilities(role LeagueAdminRole) []Capability {
+	if capabilities, ok := l.Permissions[role]; ok {
+		return capabilities
+	}
+	return DefaultRoleCapabilities[role]
+}
+
+func (l League) IsArchived() bool {
+	return l.ArchivedAt != nil
+}
+
+// IsListed reports whether the league shows up in search and suggestions.
+// Leagues created before visibility existed are public.
+func (l League) IsListed() bool {
+	return l.Visibility == "" || l.Visibility == VisibilityPublic
+}
+
+// LeagueInvite is a shareable link that adds its holder to the league until
+// it expires or is revoked.
+type LeagueInvite struct {
+	ID        string
+	LeagueID  string
+	Token     string
+	CreatedBy string
+	ExpiresAt time.Time
+	Revoked   bool
+	Uses      int
+	CreatedAt time.Time
+}
+
+func (i LeagueInvite) Active(now time.Time) bool {
+	return !i.Revoked && now.Before(i.ExpiresAt)
+}
+
+// Club is a squash venue. Its admins, besides super admins, may edit the
+// club's details, courts and opening hours.
+type Club struct {
+	ID           string
+	Name         string
+	Address      string
+	Latitude     float64
+	Longitude    float64
+	Courts       []Court
+	OpeningHours []OpeningHours
+	AdminIDs     []string
+	CreatedBy    string
 	CreatedAt    time.Time
 }
 
+type Court struct {
+	ID   string
+	Name string
+}
+
+// OpeningHours is one weekday's opening and closing time as "15:04".
+// Weekdays without an entry are closed.
+type OpeningHours struct {
+	Weekday time.Weekday
+	Opens   string
+	Closes  string
+}
+
+func (c Club) HasCoordinates() bool {
+	return c.Latitude != 0 || c.Longitude != 0
+}
+
+func (c Club) IsAdmin(userID string) bool {
+	return userID != "" && slices.Contains(c.AdminIDs, userID)
+}
+
+// CourtBooking reserves one court from Start to End. MatchID links it to
+// the scheduled match it was made for. Cancelled bookings are kept for the
+// record and free the court.
+type CourtBooking struct {
+	ID          string
+	ClubID      string
+	CourtID     string
+	UserID      string
+	MatchID     string
+	Start       time.Time
+	End         time.Time
+	CancelledAt *time.Time
+	CreatedAt   time.Time
+}
+
+func (b CourtBooking) Active() bool {
+	return b.CancelledAt == nil
+}
+
+func (b CourtBooking) Overlaps(start, end time.Time) bool {
+	return b.Start.Before(end) && start.Before(b.End)
+}
+
+func (c Club) Court(id string) (Court, bool) {
+	for _, court := range c.Courts {
+		if court.ID == id {
+			return court, true
+		}
+	}
+	return Court{}, false
+}
+
+// CalendarFeed is a secret link to an iCalendar feed. A feed without a
+// league covers everything its user plays; a league feed covers the whole
+// league.
+type CalendarFeed struct {
+	ID        string
+	UserID    string
+	LeagueID  string
+	Token     string
+	CreatedAt time.Time
+}
+
+type WithdrawalPolicy string
+
+const (
+	WithdrawalKeep         WithdrawalPolicy = "keep"
+	WithdrawalVoidAll      WithdrawalPolicy = "void_all"
+	WithdrawalVoidUnplayed WithdrawalPolicy = "void_unplayed"
+)
+
+type WithdrawalKind string
+
+const (
+	WithdrawalWithdrawn WithdrawalKind = "withdrawn"
+	WithdrawalRemoved   WithdrawalKind = "removed"
+	WithdrawalSuspended WithdrawalKind = "suspended"
+)
+
+// Withdrawal records a player who left the league, was removed by an admin
+// or is suspended. Suspended players stay in the league but cannot play
+// until reinstated; Policy applies only to players who left.
+type Withdrawal struct {
+	UserID    string
+	Kind      WithdrawalKind
+	Policy    WithdrawalPolicy
+	Reason    string
+	ByID      string
+	CreatedAt time.Time
+}
+
+func (l League) IsSuspended(userID string) bool {
+	for _, withdrawal := range l.Withdrawals {
+		if withdrawal.UserID == userID && withdrawal.Kind == WithdrawalSuspended {
+			return true
+		}
+	}
+	return false
+}
+
+type CompetitionType string
+
+const (
+	CompetitionIndividual CompetitionType = "individual"
+	CompetitionTeam       CompetitionType = "team"
+)
+
+func (l League) IsTeamLeague() bool {
+	return l.Competition == CompetitionTeam
+}
+
+type Team struct {
+	ID        string
+	Name      string
+	Club      string
+	CaptainID string
+	PlayerIDs []string
+}
+
+// Fixture is a team match. HomeOrder and AwayOrder hold the captains' order of
+// play, strongest first; once both are submitted the rubbers are created as
+// scheduled league matches linked by FixtureID.
+type Fixture struct {
+	ID         string
+	LeagueID   string
+	HomeTeamID string
+	AwayTeamID string
+	PlayAt     time.Time
+	HomeOrder  []string
+	AwayOrder  []string
+	CreatedAt  time.Time
+}
+
+type Division struct {
+	ID        string
+	Name      string
+	Level     int
+	PlayerIDs []string
+}
+
+// DivisionOf returns the division the player is assigned to.
+func (l League) DivisionOf(userID string) (Division, bool) {
+	for _, division := range l.Divisions {
+		for _, id := range division.PlayerIDs {
+			if id == userID {
+				return division, true
+			}
+		}
+	}
+	return Division{}, false
+}
+
+// StandingsSnapshot is the final table of a finished season. It is written
+// once and never recalculated, so later result changes do not rewrite history.
+type StandingsSnapshot struct {
+	LeagueID  string
+	Entries   []StandingsSnapshotEntry
+	Awards    []StandingsAward
+	CreatedAt time.Time
+}
+
+type AwardKind string
+
+const (
+	AwardChampion     AwardKind = "champion"
+	AwardMostImproved AwardKind = "most_improved"
+)
+
+type StandingsAward struct {
+	Kind         AwardKind
+	PlayerID     string
+	PlayerName   string
+	DivisionName string
+	Note         string
+}
+
+type StandingsSnapshotEntry struct {
+	Position     int
+	PlayerID     string
+	PlayerName   string
+	DivisionID   string
+	DivisionName string
+	Points       int
+	Matches      int
+	Wins         int
+	Losses       int
+	SetsWon      int
+	SetsLost     int
+	PointsWon    int
+	PointsLost   int
+	TieBreakNote string
+}
+
 type LeagueJoinRequest struct {
 	ID        string
 	LeagueID  string
@@ -81,6 +428,31 @@ type LeagueJoinRequest struct {
 
 type LeagueAdminRole string
 
+// Capability is a single league power an admin role can be granted. The
+// owner holds every capability.
+type Capability string
+
+const (
+	CapManagePlayers   Capability = "manage_players"
+	CapManageAdmins    Capability = "manage_admins"
+	CapEditResults     Capability = "edit_results"
+	CapResolveDisputes Capability = "resolve_disputes"
+	CapEndLeague       Capability = "end_league"
+	CapEditSettings    Capability = "edit_settings"
+)
+
+var (
+	LeagueAdminRoles = []LeagueAdminRole{LeagueAdminPlayer, LeagueAdminModerator}
+	Capabilities     = []Capability{CapManagePlayers, CapManageAdmins, CapEditResults, CapResolveDisputes, CapEndLeague, CapEditSettings}
+)
+
+// DefaultRoleCapabilities applies to leagues whose owner has not set up
+// their own permissions.
+var DefaultRoleCapabilities = map[LeagueAdminRole][]Capability{
+	LeagueAdminPlayer:    {CapManagePlayers, CapManageAdmins, CapEditResults, CapResolveDisputes, CapEndLeague},
+	LeagueAdminModerator: {CapManagePlayers, CapEditResults, CapResolveDisputes},
+}
+
 type LeagueStatus string
 
 const (
@@ -92,33 +464,179 @@ const (
 	LeagueStatusUpcoming LeagueStatus = "upcoming"
 )
 
+func LeagueStatusForDates(start time.Time, end *time.Time, now time.Time) LeagueStatus {
+	if end != nil && end.Before(now) {
+		return LeagueStatusFinished
+	}
+	if start.After(now) {
+		return LeagueStatusUpcoming
+	}
+	return LeagueStatusActive
+}
+
+type AutoConfirmAction string
+
+const (
+	AutoConfirmConfirm  AutoConfirmAction = "confirm"
+	AutoConfirmEscalate AutoConfirmAction = "escalate"
+)
+
+type TieBreakRule string
+
+const (
+	TieBreakHeadToHead      TieBreakRule = "head_to_head"
+	TieBreakMiniLeague      TieBreakRule = "mini_league"
+	TieBreakSetDifference   TieBreakRule = "set_difference"
+	TieBreakPointDifference TieBreakRule = "point_difference"
+)
+
+type MatchOutcome string
+
+const (
+	OutcomeNormal        MatchOutcome = "normal"
+	OutcomeWalkover      MatchOutcome = "walkover"
+	OutcomeRetired       MatchOutcome = "retired"
+	OutcomeDoubleForfeit MatchOutcome = "double_forfeit"
+)
+
+// ScoringRules holds league points per match. Forfeit is awarded to a player
+// who conceded a walkover and to both players of a double forfeit.
+type ScoringRules struct {
+	Win     int
+	Loss    int
+	Forfeit int
+}
+
+func (r ScoringRules) IsZero() bool {
+	return r.Win == 0 && r.Loss == 0 && r.Forfeit == 0
+}
+
 type SetScore struct {
 	A int
 	B int
 }
 
 type Match struct {
-	ID          string
-	LeagueID    string
-	PlayerAID   string
-	PlayerBID   string
+	ID        string
+	LeagueID  string
+	PlayerAID string
+	PlayerBID string
+	// PartnerAID and PartnerBID are set for doubles, where each side is a
+	// pair. PlayerAID and PlayerBID still identify the sides.
+	PartnerAID string
+	PartnerBID string
+	// FixtureID and Rubber place the match in a team fixture; Rubber is the
+	// 1-based position in the order of play.
+	FixtureID string
+	Rubber    int
+	// HeadStartA and HeadStartB are the points each side starts every set
+	// with, fixed from the handicaps when the match is created, so players
+	// know them before they play.
+	HeadStartA  int
+	HeadStartB  int
 	Sets        []SetScore
+	Outcome     MatchOutcome
+	ConcededBy  string
 	Status      MatchStatus
 	ReportedBy  string
 	ConfirmedBy string
-	CreatedAt   time.Time
+	// AutoConfirmed marks results confirmed by the system after the
+	// confirmation window passed; ConfirmedBy stays empty.
+	AutoConfirmed bool
+	Dispute       *MatchDispute
+	// ScheduledAt and Venue are the agreed time and place of a match that
+	// has not been played yet. Proposal holds the times still on offer.
+	// ClubID is set when the venue is a registered club.
+	ScheduledAt *time.Time
+	Venue       string
+	ClubID      string
+	Proposal    *ScheduleProposal
+	// ReportedAt is when the result of a scheduled match was entered.
+	// Matches created together with their result leave it nil.
+	ReportedAt *time.Time
+	CreatedAt  time.Time
+}
+
+// MaxScheduleSlots caps how many times one proposal may offer.
+const MaxScheduleSlots = 3
+
+// ScheduleProposal is the set of times one side offers for a match. The
+// other side accepts one of them or answers with a proposal of its own.
+type ScheduleProposal struct {
+	ProposedBy string
+	Slots      []ScheduleSlot
+	CreatedAt  time.Time
+}
+
+type ScheduleSlot struct {
+	At     time.Time
+	Venue  string
+	ClubID string
+}
+
+// MatchDispute is the score proposed by the player who rejected a reported
+// result. It stays on the match until the dispute is resolved.
+type MatchDispute struct {
+	RaisedBy   string
+	Sets       []SetScore
+	Outcome    MatchOutcome
+	ConcededBy string
+	Escalated  bool
+	CreatedAt  time.Time
+}
+
+// DisputeRaisedBySystem stands in for the author of disputes opened because
+// nobody confirmed the result in time.
+const DisputeRaisedBySystem = "system"
+
+func (d MatchDispute) BySystem() bool {
+	return d.RaisedBy == DisputeRaisedBySystem
+}
+
+type MatchRevisionAction string
+
+const (
+	RevisionReported         MatchRevisionAction = "reported"
+	RevisionCorrected        MatchRevisionAction = "corrected"
+	RevisionDisputed         MatchRevisionAction = "disputed"
+	RevisionDisputeAccepted  MatchRevisionAction = "dispute_accepted"
+	RevisionDisputeEscalated MatchRevisionAction = "dispute_escalated"
+	RevisionDisputeResolved  MatchRevisionAction = "dispute_resolved"
+	RevisionAutoConfirmed    MatchRevisionAction = "auto_confirmed"
+	RevisionAutoEscalated    MatchRevisionAction = "auto_escalated"
+	RevisionVoided           MatchRevisionAction = "voided"
+)
+
+// MatchRevision is a snapshot of a match result after a change, kept so the
+// full history of corrections can be shown and audited.
+type MatchRevision struct {
+	ID         string
+	MatchID    string
+	Action     MatchRevisionAction
+	Sets       []SetScore
+	Outcome    MatchOutcome
+	ConcededBy string
+	Status     MatchStatus
+	ChangedBy  string
+	Reason     string
+	CreatedAt  time.Time
 }
 
 type FriendlyMatch struct {
-	ID          string
-	PlayerAID   string
-	PlayerBID   string
-	Sets        []SetScore
-	Status      MatchStatus
-	ReportedBy  string
-	ConfirmedBy string
-	PlayedAt    time.Time
-	CreatedAt   time.Time
+	ID            string
+	PlayerAID     string
+	PlayerBID     string
+	PartnerAID    string
+	PartnerBID    string
+	Sets          []SetScore
+	Outcome       MatchOutcome
+	ConcededBy    string
+	Status        MatchStatus
+	ReportedBy    string
+	ConfirmedBy   string
+	AutoConfirmed bool
+	PlayedAt      time.Time
+	CreatedAt     time.Time
 }
 
 type ReportType string
@@ -141,3 +659,37 @@ type Report struct {
 	Status      ReportStatus
 	CreatedAt   time.Time
 }
+
+func (m Match) SideA() []string   { return matchSide(m.PlayerAID, m.PartnerAID) }
+func (m Match) SideB() []string   { return matchSide(m.PlayerBID, m.PartnerBID) }
+func (m Match) IsDoubles() bool   { return m.PartnerAID != "" || m.PartnerBID != "" }
+func (m Match) Players() []string { return append(m.SideA(), m.SideB()...) }
+
+func (m Match) Involves(userID string) bool {
+	return userID != "" && slices.Contains(m.Players(), userID)
+}
+
+// ReportTime is when the result was entered, which is also when the match
+// counts as played.
+func (m Match) ReportTime() time.Time {
+	if m.ReportedAt != nil {
+		return *m.ReportedAt
+	}
+	return m.CreatedAt
+}
+
+func (m FriendlyMatch) SideA() []string   { return matchSide(m.PlayerAID, m.PartnerAID) }
+func (m FriendlyMatch) SideB() []string   { return matchSide(m.PlayerBID, m.PartnerBID) }
+func (m FriendlyMatch) IsDoubles() bool   { return m.PartnerAID != "" || m.PartnerBID != "" }
+func (m FriendlyMatch) Players() []string { return append(m.SideA(), m.SideB()...) }
+
+func (m FriendlyMatch) Involves(userID string) bool {
+	return userID != "" && slices.Contains(m.Players(), userID)
+}
+
+func matchSide(player, partner string) []string {
+	if partner == "" {
+		return []string{player}
+	}
+	return []string{player, partner}
+}
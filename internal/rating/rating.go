@@ -0,0 +1,288 @@
+// Package rating computes Glicko-2 player ratings from match results. It
+// keeps no state: Compute replays every game from the start, so an edited or
+// removed result is reflected the next time ratings are computed.
+package rating
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	DefaultRating     = 1500.0
+	DefaultDeviation  = 350.0
+	DefaultVolatility = 0.06
+	// Tau limits how fast volatility changes between rating periods.
+	Tau = 0.5
+	// PeriodLength groups games into rating periods. All games of a period
+	// are rated together, against the ratings from before the period.
+	PeriodLength = 7 * 24 * time.Hour
+	// ProvisionalDeviation marks ratings that are still a rough guess.
+	ProvisionalDeviation = 150.0
+
+	scale       = 173.7178
+	convergence = 0.000001
+)
+
+type Rating struct {
+	Rating     float64
+	Deviation  float64
+	Volatility float64
+	Games      int
+	LastPlayed time.Time
+}
+
+func (r Rating) Provisional() bool {
+	return r.Games == 0 || r.Deviation >= ProvisionalDeviation
+}
+
+// Game is one result to rate. Score is 1 when side A won and 0 when side B
+// won. In doubles each player is rated against the other pair as a whole.
+type Game struct {
+	ID       string
+	PlayedAt time.Time
+	SideA    []string
+	SideB    []string
+	Score    float64
+}
+
+type player struct {
+	mu         float64
+	phi        float64
+	sigma      float64
+	games      int
+	lastPlayed time.Time
+}
+
+type opponent struct {
+	mu    float64
+	phi   float64
+	score float64
+}
+
+// Compute rates the games in chronological order, one period at a time up
+// to now, and returns the ratings keyed by player ID.
+func Compute(games []Game, now time.Time) map[string]Rating {
+	players := replay(games, now, nil)
+	ratings := make(map[string]Rating, len(players))
+	for id, p := range players {
+		ratings[id] = p.rating()
+	}
+	return ratings
+}
+
+// Point is a player's rating at the end of a period in which they played,
+// dated with their last game of that period.
+type Point struct {
+	At time.Time
+	Rating
+}
+
+// History replays the games like Compute and returns how the player's rating
+// changed, oldest first.
+func History(games []Game, playerID string, now time.Time) []Point {
+	points := []Point{}
+	played := 0
+	replay(games, now, func(players map[string]*player) {
+		p, ok := players[playerID]
+		if !ok || p.games == played {
+			return
+		}
+		played = p.games
+		points = append(points, Point{At: p.lastPlayed, Rating: p.rating()})
+	})
+	return points
+}
+
+// replay rates the games period by period and calls afterPeriod, when set,
+// once each period has been applied.
+func replay(games []Game, now time.Time, afterPeriod func(map[string]*player)) map[string]*player {
+	players := map[string]*player{}
+	sorted := sortGames(games)
+	if len(sorted) == 0 {
+		return players
+	}
+	start := sorted[0].PlayedAt
+	for i := 0; i < len(sorted) || !start.After(now); start = start.Add(PeriodLength) {
+		end := start.Add(PeriodLength)
+		j := i
+		for j < len(sorted) && sorted[j].PlayedAt.Before(end) {
+			j++
+		}
+		ratePeriod(players, sorted[i:j])
+		if afterPeriod != nil {
+			afterPeriod(players)
+		}
+		i = j
+	}
+	return players
+}
+
+// Expires returns when ratings computed from the games at now go stale
+// without any new game: the start of the next rating period. It is zero when
+// there are no games, as the ratings never change then.
+func Expires(games []Game, now time.Time) time.Time {
+	sorted := sortGames(games)
+	if len(sorted) == 0 {
+		return time.Time{}
+	}
+	first := sorted[0].PlayedAt
+	if now.Before(first) {
+		return first
+	}
+	return first.Add((now.Sub(first)/PeriodLength + 1) * PeriodLength)
+}
+
+// Default is the rating of a player without rated games.
+func Default() Rating {
+	return Rating{Rating: DefaultRating, Deviation: DefaultDeviation, Volatility: DefaultVolatility}
+}
+
+func sortGames(games []Game) []Game {
+	sorted := append([]Game{}, games...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].PlayedAt.Equal(sorted[j].PlayedAt) {
+			return sorted[i].PlayedAt.Before(sorted[j].PlayedAt)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+	return sorted
+}
+
+func ratePeriod(players map[string]*player, games []Game) {
+	opponents := map[string][]opponent{}
+	for _, game := range games {
+		sideA := lookup(players, game.SideA)
+		sideB := lookup(players, game.SideB)
+		muA, phiA := sideStrength(sideA)
+		muB, phiB := sideStrength(sideB)
+		for _, id := range game.SideA {
+			opponents[id] = append(opponents[id], opponent{mu: muB, phi: phiB, score: game.Score})
+		}
+		for _, id := range game.SideB {
+			opponents[id] = append(opponents[id], opponent{mu: muA, phi: phiA, score: 1 - game.Score})
+		}
+		for _, p := range append(sideA, sideB...) {
+			p.games++
+			if game.PlayedAt.After(p.lastPlayed) {
+				p.lastPlayed = game.PlayedAt
+			}
+		}
+	}
+
+	// Every update reads the ratings from before the period, so they are
+	// computed first and applied afterwards.
+	updated := make(map[string]player, len(players))
+	for id, p := range players {
+		updated[id] = update(*p, opponents[id])
+	}
+	for id, next := range updated {
+		*players[id] = next
+	}
+}
+
+func lookup(players map[string]*player, ids []string) []*player {
+	side := make([]*player, 0, len(ids))
+	for _, id := range ids {
+		p, ok := players[id]
+		if !ok {
+			p = &player{phi: DefaultDeviation / scale, sigma: DefaultVolatility}
+			players[id] = p
+		}
+		side = append(side, p)
+	}
+	return side
+}
+
+// sideStrength treats a pair as one opponent with the average rating and the
+// root mean square deviation of its players.
+func sideStrength(side []*player) (float64, float64) {
+	if len(side) == 0 {
+		return 0, DefaultDeviation / scale
+	}
+	var mu, phi2 float64
+	for _, p := range side {
+		mu += p.mu
+		phi2 += p.phi * p.phi
+	}
+	n := float64(len(side))
+	return mu / n, math.Sqrt(phi2 / n)
+}
+
+func update(p player, opponents []opponent) player {
+	if len(opponents) == 0 {
+		p.phi = math.Min(math.Sqrt(p.phi*p.phi+p.sigma*p.sigma), DefaultDeviation/scale)
+		return p
+	}
+
+	var variance, improvement float64
+	for _, o := range opponents {
+		g := gFactor(o.phi)
+		e := expected(p.mu, o.mu, g)
+		variance += g * g * e * (1 - e)
+		improvement += g * (o.score - e)
+	}
+	v := 1 / variance
+	delta := v * improvement
+
+	sigma := volatility(p.phi, p.sigma, v, delta)
+	phiStar := math.Sqrt(p.phi*p.phi + sigma*sigma)
+	p.phi = 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	p.mu += p.phi * p.phi * improvement
+	p.sigma = sigma
+	return p
+}
+
+func gFactor(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+func expected(mu, opponentMu, g float64) float64 {
+	return 1 / (1 + math.Exp(-g*(mu-opponentMu)))
+}
+
+// volatility finds the new volatility with the Illinois variant of regula
+// falsi, as in step 5 of Glickman's description of Glicko-2.
+func volatility(phi, sigma, v, delta float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(Tau*Tau)
+	}
+
+	A := a
+	var B float64
+	if delta*delta > phi*phi+v {
+		B = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*Tau) < 0 {
+			k++
+		}
+		B = a - k*Tau
+	}
+	fA, fB := f(A), f(B)
+	for math.Abs(B-A) > convergence {
+		C := A + (A-B)*fA/(fB-fA)
+		fC := f(C)
+		if fC*fB <= 0 {
+			A, fA = B, fB
+		} else {
+			fA /= 2
+		}
+		B, fB = C, fC
+	}
+	return math.Exp(A / 2)
+}
+
+func (p player) rating() Rating {
+	return Rating{
+		Rating:     DefaultRating + scale*p.mu,
+		Deviation:  scale * p.phi,
+		Volatility: p.sigma,
+		Games:      p.games,
+		LastPlayed: p.lastPlayed,
+	}
+}
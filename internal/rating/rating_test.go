@@ -0,0 +1,92 @@
+package rating
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// The example from Glickman's "Example of the Glicko-2 system": a 1500/200
+// player beats a 1400/30 opponent and loses to 1550/100 and 1700/300.
+func TestUpdateMatchesGlickmanExample(t *testing.T) {
+	p := player{mu: 0, phi: 200 / scale, sigma: 0.06}
+	opponents := []opponent{
+		{mu: (1400 - DefaultRating) / scale, phi: 30 / scale, score: 1},
+		{mu: (1550 - DefaultRating) / scale, phi: 100 / scale, score: 0},
+		{mu: (1700 - DefaultRating) / scale, phi: 300 / scale, score: 0},
+	}
+
+	got := update(p, opponents).rating()
+
+	tests := []struct {
+		name      string
+		got, want float64
+		tolerance float64
+	}{
+		{"rating", got.Rating, 1464.06, 0.01},
+		{"deviation", got.Deviation, 151.52, 0.01},
+		{"volatility", got.Volatility, 0.05999, 0.00001},
+	}
+	for _, tt := range tests {
+		if math.Abs(tt.got-tt.want) > tt.tolerance {
+			t.Errorf("%s = %.5f, want %.5f", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestUpdateWithoutGamesOnlyWidensDeviation(t *testing.T) {
+	p := player{mu: 0.5, phi: 50 / scale, sigma: 0.06}
+
+	got := update(p, nil)
+
+	if got.mu != p.mu || got.sigma != p.sigma {
+		t.Fatalf("rating or volatility changed: %+v", got)
+	}
+	want := math.Sqrt(p.phi*p.phi + p.sigma*p.sigma)
+	if math.Abs(got.phi-want) > 1e-12 {
+		t.Errorf("phi = %v, want %v", got.phi, want)
+	}
+}
+
+func TestComputeRatesGamesOfAPeriodTogether(t *testing.T) {
+	start := time.Date(2026, 1, 5, 18, 0, 0, 0, time.UTC)
+	games := []Game{
+		{ID: "1", PlayedAt: start, SideA: []string{"a"}, SideB: []string{"b"}, Score: 1},
+		{ID: "2", PlayedAt: start.Add(time.Hour), SideA: []string{"a"}, SideB: []string{"c"}, Score: 1},
+	}
+
+	ratings := Compute(games, start.Add(2*time.Hour))
+
+	// b and c lost to the same unrated player in one period, so they end
+	// level whatever the order of the games.
+	if b, c := ratings["b"], ratings["c"]; b.Rating != c.Rating || b.Deviation != c.Deviation || b.Games != 1 {
+		t.Errorf("b = %+v, c = %+v", b, c)
+	}
+	if a := ratings["a"]; a.Games != 2 || a.Rating <= DefaultRating || !a.LastPlayed.Equal(start.Add(time.Hour)) {
+		t.Errorf("a = %+v", a)
+	}
+}
+
+func TestExpires(t *testing.T) {
+	first := time.Date(2026, 1, 5, 18, 0, 0, 0, time.UTC)
+	games := []Game{{ID: "1", PlayedAt: first, SideA: []string{"a"}, SideB: []string{"b"}, Score: 1}}
+
+	tests := []struct {
+		name  string
+		games []Game
+		now   time.Time
+		want  time.Time
+	}{
+		{"no games", nil, first, time.Time{}},
+		{"before the first game", games, first.Add(-time.Hour), first},
+		{"first period", games, first.Add(time.Hour), first.Add(PeriodLength)},
+		{"period boundary", games, first.Add(PeriodLength), first.Add(2 * PeriodLength)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Expires(tt.games, tt.now); !got.Equal(tt.want) {
+				t.Errorf("Expires = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package store
 
 import (
 	"errors"
+	"maps"
 	"math/rand"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -23,6 +25,15 @@ type MemoryStore struct {
 	friendlies map[string]model.FriendlyMatch
 	reports    map[string]model.Report
 	requests   map[string]model.LeagueJoinRequest
+	revisions  map[string][]model.MatchRevision
+	snapshots  map[string]model.StandingsSnapshot
+	fixtures   map[string]model.Fixture
+	invites    map[string]model.LeagueInvite
+	feeds      map[string]model.CalendarFeed
+	clubs      map[string]model.Club
+	bookings   map[string]model.CourtBooking
+	// results counts match writes for ResultsVersion.
+	results int64
 }
 
 func NewMemoryStore() *MemoryStore {
@@ -33,6 +44,13 @@ func NewMemoryStore() *MemoryStore {
 		friendlies: make(map[string]model.FriendlyMatch),
 		reports:    make(map[string]model.Report),
 		requests:   make(map[string]model.LeagueJoinRequest),
+		revisions:  make(map[string][]model.MatchRevision),
+		snapshots:  make(map[string]model.StandingsSnapshot),
+		fixtures:   make(map[string]model.Fixture),
+		invites:    make(map[string]model.LeagueInvite),
+		feeds:      make(map[string]model.CalendarFeed),
+		clubs:      make(map[string]model.Club),
+		bookings:   make(map[string]model.CourtBooking),
 	}
 	if strings.ToLower(strings.TrimSpace(os.Getenv("APP"))) != "prod" {
 		seedData(s)
@@ -158,6 +176,21 @@ func (s *MemoryStore) AddPlayerToLeague(leagueID, userID string) error {
 	return nil
 }
 
+func (s *MemoryStore) RemovePlayerFromLeague(leagueID, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	league, ok := s.leagues[leagueID]
+	if !ok {
+		return errors.New("league not found")
+	}
+	if !containsID(league.PlayerIDs, userID) {
+		return errors.New("player not found")
+	}
+	s.leagues[leagueID] = withoutPlayer(league, userID)
+	return nil
+}
+
 func (s *MemoryStore) AddAdminToLeague(leagueID, userID string, role model.LeagueAdminRole) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -291,6 +324,199 @@ func (s *MemoryStore) HasPendingJoinRequest(leagueID, userID string) bool {
 	return false
 }
 
+func (s *MemoryStore) CreateLeagueInvite(invite model.LeagueInvite) (model.LeagueInvite, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.leagues[invite.LeagueID]; !ok {
+		return model.LeagueInvite{}, errors.New("league not found")
+	}
+	if invite.ID == "" {
+		invite.ID = uuid.NewString()
+	}
+	if invite.CreatedAt.IsZero() {
+		invite.CreatedAt = time.Now()
+	}
+	s.invites[invite.ID] = invite
+	return invite, nil
+}
+
+func (s *MemoryStore) ListLeagueInvites(leagueID string) []model.LeagueInvite {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	invites := make([]model.LeagueInvite, 0)
+	for _, invite := range s.invites {
+		if invite.LeagueID == leagueID {
+			invites = append(invites, invite)
+		}
+	}
+	sort.Slice(invites, func(i, j int) bool { return invites[i].CreatedAt.After(invites[j].CreatedAt) })
+	return invites
+}
+
+func (s *MemoryStore) GetLeagueInviteByToken(token string) (model.LeagueInvite, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, invite := range s.invites {
+		if invite.Token == token {
+			return invite, true
+		}
+	}
+	return model.LeagueInvite{}, false
+}
+
+func (s *MemoryStore) UpdateLeagueInvite(invite model.LeagueInvite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.invites[invite.ID]; !ok {
+		return errors.New("invite not found")
+	}
+	s.invites[invite.ID] = invite
+	return nil
+}
+
+func (s *MemoryStore) GetCalendarFeed(userID, leagueID string) (model.CalendarFeed, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, feed := range s.feeds {
+		if feed.UserID == userID && feed.LeagueID == leagueID {
+			return feed, true
+		}
+	}
+	return model.CalendarFeed{}, false
+}
+
+func (s *MemoryStore) GetCalendarFeedByToken(token string) (model.CalendarFeed, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, feed := range s.feeds {
+		if feed.Token == token {
+			return feed, true
+		}
+	}
+	return model.CalendarFeed{}, false
+}
+
+// SaveCalendarFeed creates the feed or, when the user already has one for
+// the league, replaces its token.
+func (s *MemoryStore) SaveCalendarFeed(feed model.CalendarFeed) (model.CalendarFeed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, existing := range s.feeds {
+		if existing.UserID == feed.UserID && existing.LeagueID == feed.LeagueID {
+			existing.Token = feed.Token
+			s.feeds[id] = existing
+			return existing, nil
+		}
+	}
+	if feed.ID == "" {
+		feed.ID = uuid.NewString()
+	}
+	if feed.CreatedAt.IsZero() {
+		feed.CreatedAt = time.Now()
+	}
+	s.feeds[feed.ID] = feed
+	return feed, nil
+}
+
+func (s *MemoryStore) ListClubs() []model.Club {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	clubs := make([]model.Club, 0, len(s.clubs))
+	for _, club := range s.clubs {
+		clubs = append(clubs, club)
+	}
+	sort.Slice(clubs, func(i, j int) bool { return clubs[i].Name < clubs[j].Name })
+	return clubs
+}
+
+func (s *MemoryStore) GetClub(id string) (model.Club, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	club, ok := s.clubs[id]
+	return club, ok
+}
+
+func (s *MemoryStore) CreateClub(club model.Club) (model.Club, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if club.ID == "" {
+		club.ID = uuid.NewString()
+	}
+	if club.CreatedAt.IsZero() {
+		club.CreatedAt = time.Now()
+	}
+	s.clubs[club.ID] = club
+	return club, nil
+}
+
+func (s *MemoryStore) UpdateClub(club model.Club) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.clubs[club.ID]; !ok {
+		return errors.New("club not found")
+	}
+	s.clubs[club.ID] = club
+	return nil
+}
+
+func (s *MemoryStore) ListCourtBookings(clubID string) []model.CourtBooking {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	bookings := []model.CourtBooking{}
+	for _, booking := range s.bookings {
+		if booking.ClubID == clubID {
+			bookings = append(bookings, booking)
+		}
+	}
+	sort.Slice(bookings, func(i, j int) bool { return bookings[i].Start.Before(bookings[j].Start) })
+	return bookings
+}
+
+func (s *MemoryStore) GetCourtBooking(id string) (model.CourtBooking, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	booking, ok := s.bookings[id]
+	return booking, ok
+}
+
+func (s *MemoryStore) CreateCourtBooking(booking model.CourtBooking) (model.CourtBooking, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if booking.ID == "" {
+		booking.ID = uuid.NewString()
+	}
+	if booking.CreatedAt.IsZero() {
+		booking.CreatedAt = time.Now()
+	}
+	s.bookings[booking.ID] = booking
+	return booking, nil
+}
+
+func (s *MemoryStore) UpdateCourtBooking(booking model.CourtBooking) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.bookings[booking.ID]; !ok {
+		return errors.New("booking not found")
+	}
+	s.bookings[booking.ID] = booking
+	return nil
+}
+
 func (s *MemoryStore) ListMatches(leagueID string) []model.Match {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -324,6 +550,7 @@ func (s *MemoryStore) CreateMatch(match model.Match) (model.Match, error) {
 		match.CreatedAt = time.Now()
 	}
 	s.matches[match.ID] = match
+	s.results++
 	return match, nil
 }
 
@@ -335,6 +562,57 @@ func (s *MemoryStore) UpdateMatch(match model.Match) error {
 		return errors.New("match not found")
 	}
 	s.matches[match.ID] = match
+	s.results++
+	return nil
+}
+
+func (s *MemoryStore) ListFixtures(leagueID string) []model.Fixture {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	fixtures := make([]model.Fixture, 0)
+	for _, f := range s.fixtures {
+		if f.LeagueID == leagueID {
+			fixtures = append(fixtures, f)
+		}
+	}
+	sort.Slice(fixtures, func(i, j int) bool { return fixtures[i].PlayAt.Before(fixtures[j].PlayAt) })
+	return fixtures
+}
+
+func (s *MemoryStore) GetFixture(id string) (model.Fixture, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	f, ok := s.fixtures[id]
+	return f, ok
+}
+
+func (s *MemoryStore) CreateFixture(fixture model.Fixture) (model.Fixture, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.leagues[fixture.LeagueID]; !ok {
+		return model.Fixture{}, errors.New("league not found")
+	}
+	if fixture.ID == "" {
+		fixture.ID = uuid.NewString()
+	}
+	if fixture.CreatedAt.IsZero() {
+		fixture.CreatedAt = time.Now()
+	}
+	s.fixtures[fixture.ID] = fixture
+	return fixture, nil
+}
+
+func (s *MemoryStore) UpdateFixture(fixture model.Fixture) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.fixtures[fixture.ID]; !ok {
+		return errors.New("fixture not found")
+	}
+	s.fixtures[fixture.ID] = fixture
 	return nil
 }
 
@@ -372,6 +650,7 @@ func (s *MemoryStore) CreateFriendlyMatch(match model.FriendlyMatch) (model.Frie
 		match.PlayedAt = match.CreatedAt
 	}
 	s.friendlies[match.ID] = match
+	s.results++
 	return match, nil
 }
 
@@ -383,9 +662,66 @@ func (s *MemoryStore) UpdateFriendlyMatch(match model.FriendlyMatch) error {
 		return errors.New("friendly match not found")
 	}
 	s.friendlies[match.ID] = match
+	s.results++
 	return nil
 }
 
+func (s *MemoryStore) ResultsVersion() (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.results, true
+}
+
+func (s *MemoryStore) GetStandingsSnapshot(leagueID string) (model.StandingsSnapshot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot, ok := s.snapshots[leagueID]
+	return snapshot, ok
+}
+
+func (s *MemoryStore) CreateStandingsSnapshot(snapshot model.StandingsSnapshot) (model.StandingsSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.leagues[snapshot.LeagueID]; !ok {
+		return model.StandingsSnapshot{}, errors.New("league not found")
+	}
+	if _, ok := s.snapshots[snapshot.LeagueID]; ok {
+		return model.StandingsSnapshot{}, errors.New("standings snapshot already exists")
+	}
+	if snapshot.CreatedAt.IsZero() {
+		snapshot.CreatedAt = time.Now()
+	}
+	s.snapshots[snapshot.LeagueID] = snapshot
+	return snapshot, nil
+}
+
+func (s *MemoryStore) ListMatchRevisions(matchID string) []model.MatchRevision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]model.MatchRevision{}, s.revisions[matchID]...)
+}
+
+func (s *MemoryStore) CreateMatchRevision(revision model.MatchRevision) (model.MatchRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.matches[revision.MatchID]; !ok {
+		return model.MatchRevision{}, errors.New("match not found")
+	}
+	if revision.ID == "" {
+		revision.ID = uuid.NewString()
+	}
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+	s.revisions[revision.MatchID] = append(s.revisions[revision.MatchID], revision)
+	return revision, nil
+}
+
 func (s *MemoryStore) ListReports() []model.Report {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -426,6 +762,28 @@ func hashPassword(password string) string {
 	return string(hash)
 }
 
+func seedClub(name, adminID string) model.Club {
+	club := model.Club{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Address:   "Warszawa",
+		AdminIDs:  []string{adminID},
+		CreatedBy: adminID,
+		CreatedAt: time.Now(),
+	}
+	for i := 1; i <= 3; i++ {
+		club.Courts = append(club.Courts, model.Court{ID: uuid.NewString(), Name: "Kort " + strconv.Itoa(i)})
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		hours := model.OpeningHours{Weekday: day, Opens: "07:00", Closes: "23:00"}
+		if day == time.Saturday || day == time.Sunday {
+			hours.Opens, hours.Closes = "09:00", "21:00"
+		}
+		club.OpeningHours = append(club.OpeningHours, hours)
+	}
+	return club
+}
+
 func seedData(s *MemoryStore) {
 	rng := rand.New(rand.NewSource(42))
 	defaultHash := hashPassword("password123")
@@ -485,15 +843,19 @@ func seedData(s *MemoryStore) {
 			end := startDate.AddDate(0, 0, 30+rng.Intn(120))
 			endDate = &end
 		}
+		club := seedClub(ln.Location, owner.ID)
+		s.clubs[club.ID] = club
 		league := model.League{
 			ID:           uuid.NewString(),
 			Name:         ln.Name,
 			Description:  ln.Description,
 			Location:     ln.Location,
+			ClubID:       club.ID,
 			OwnerID:      owner.ID,
 			AdminRoles:   map[string]model.LeagueAdminRole{owner.ID: model.LeagueAdminPlayer},
 			PlayerIDs:    playerIDs,
 			SetsPerMatch: ln.Sets,
+			PointsPerSet: 11,
 			StartDate:    startDate,
 			EndDate:      endDate,
 			Status:       leagueStatusForSeed(startDate, endDate, time.Now()),
@@ -643,6 +1005,32 @@ func pickOpponentExcluding(playerIDs []string, excludeID string, rng *rand.Rand)
 	return candidates[rng.Intn(len(candidates))]
 }
 
+// withoutPlayer drops the player from the league roster and its teams, along
+// with any admin role they held there. Division membership and matches stay,
+// so the player's past results keep their place in the division table.
+func withoutPlayer(league model.League, userID string) model.League {
+	league.PlayerIDs = removeID(league.PlayerIDs, userID)
+	teams := make([]model.Team, len(league.Teams))
+	for i, team := range league.Teams {
+		team.PlayerIDs = removeID(team.PlayerIDs, userID)
+		teams[i] = team
+	}
+	league.Teams = teams
+	league.AdminRoles = maps.Clone(league.AdminRoles)
+	delete(league.AdminRoles, userID)
+	return league
+}
+
+func removeID(ids []string, needle string) []string {
+	kept := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != needle {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
 func containsID(ids []string, needle string) bool {
 	for _, id := range ids {
 		if id == needle {
@@ -695,15 +1083,23 @@ func randomSets(rng *rand.Rand, maxSets int) []model.SetScore {
 	if maxSets < 3 {
 		maxSets = 3
 	}
-	totalSets := 3 + rng.Intn(maxSets-2)
-	sets := make([]model.SetScore, 0, totalSets)
-	for i := 0; i < totalSets; i++ {
-		a := 11 + rng.Intn(6)
-		b := 11 + rng.Intn(6)
-		if a == b {
-			b += 2
+	setsToWin := maxSets/2 + 1
+	sets := make([]model.SetScore, 0, maxSets)
+	wonA, wonB := 0, 0
+	for wonA < setsToWin && wonB < setsToWin {
+		winner := 11
+		loser := rng.Intn(10)
+		if rng.Intn(4) == 0 {
+			loser = 10 + rng.Intn(4)
+			winner = loser + 2
+		}
+		if rng.Intn(2) == 0 {
+			sets = append(sets, model.SetScore{A: winner, B: loser})
+			wonA++
+		} else {
+			sets = append(sets, model.SetScore{A: loser, B: winner})
+			wonB++
 		}
-		sets = append(sets, model.SetScore{A: a, B: b})
 	}
 	return sets
 }
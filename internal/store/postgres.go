@@ -15,6 +15,22 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+const leagueColumns = `id, name, description, location, owner_id, admin_roles, player_ids, sets_per_match, points_per_set, scoring, tie_breakers, confirmation_hours, auto_confirm_action, start_date, end_date, status, season, previous_season_id, divisions, promotion_count, doubles, competition, rubbers_per_fixture, teams, handicap, handicaps, withdrawal_policy, withdrawals, visibility, join_code, max_players, waitlist, archived_at, ownership_transfer, permissions, club_id, created_at`
+
+const matchColumns = `id, league_id, player_a_id, player_b_id, partner_a_id, partner_b_id, fixture_id, rubber, head_start_a, head_start_b, sets_json, outcome, conceded_by, status, reported_by, confirmed_by, auto_confirmed, dispute, scheduled_at, venue, proposal, club_id, reported_at, created_at`
+
+const fixtureColumns = `id, league_id, home_team_id, away_team_id, play_at, home_order, away_order, created_at`
+
+const inviteColumns = `id, league_id, token, created_by, expires_at, revoked, uses, created_at`
+
+const calendarFeedColumns = `id, user_id, league_id, token, created_at`
+
+const courtBookingColumns = `id, club_id, court_id, user_id, match_id, start_at, end_at, cancelled_at, created_at`
+
+const clubColumns = `id, name, address, latitude, longitude, courts, opening_hours, admin_ids, created_by, created_at`
+
+const friendlyMatchColumns = `id, player_a_id, player_b_id, partner_a_id, partner_b_id, sets_json, outcome, conceded_by, status, reported_by, confirmed_by, auto_confirmed, played_at, created_at`
+
 type PostgresStore struct {
 	db *sql.DB
 }
@@ -108,7 +124,7 @@ func (s *PostgresStore) CreateUser(user model.User) (model.User, error) {
 }
 
 func (s *PostgresStore) ListLeagues() []model.League {
-	rows, err := s.db.Query(`SELECT id, name, description, location, owner_id, admin_roles, player_ids, sets_per_match, start_date, end_date, status, created_at FROM leagues`)
+	rows, err := s.db.Query(`SELECT ` + leagueColumns + ` FROM leagues`)
 	if err != nil {
 		return nil
 	}
@@ -127,7 +143,7 @@ func (s *PostgresStore) ListLeagues() []model.League {
 }
 
 func (s *PostgresStore) GetLeague(id string) (model.League, bool) {
-	row := s.db.QueryRow(`SELECT id, name, description, location, owner_id, admin_roles, player_ids, sets_per_match, start_date, end_date, status, created_at FROM leagues WHERE id = $1`, id)
+	row := s.db.QueryRow(`SELECT `+leagueColumns+` FROM leagues WHERE id = $1`, id)
 	league, err := scanLeagueRow(row)
 	if err != nil {
 		return model.League{}, false
@@ -147,9 +163,12 @@ func (s *PostgresStore) CreateLeague(league model.League) (model.League, error)
 	}
 	adminJSON := toJSON(league.AdminRoles)
 	playerJSON := toJSON(league.PlayerIDs)
+	scoringJSON := toJSON(league.Scoring)
+	tieBreakJSON := toJSON(league.TieBreakers)
+	divisionJSON := toJSON(league.Divisions)
 
-	_, err := s.db.Exec(`INSERT INTO leagues (id, name, description, location, owner_id, admin_roles, player_ids, sets_per_match, start_date, end_date, status, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)`,
-		league.ID, league.Name, league.Description, league.Location, league.OwnerID, adminJSON, playerJSON, league.SetsPerMatch, timeValuePtr(league.StartDate), timePtrValue(league.EndDate), string(league.Status), timeValuePtr(league.CreatedAt),
+	_, err := s.db.Exec(`INSERT INTO leagues (`+leagueColumns+`) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37)`,
+		league.ID, league.Name, league.Description, league.Location, league.OwnerID, adminJSON, playerJSON, league.SetsPerMatch, league.PointsPerSet, scoringJSON, tieBreakJSON, league.ConfirmationHours, string(league.AutoConfirmAction), timeValuePtr(league.StartDate), timePtrValue(league.EndDate), string(league.Status), league.Season, league.PreviousSeasonID, divisionJSON, league.PromotionCount, league.Doubles, string(league.Competition), league.RubbersPerFixture, toJSON(league.Teams), league.Handicap, toJSON(league.Handicaps), string(league.WithdrawalPolicy), toJSON(league.Withdrawals), string(league.Visibility), league.JoinCode, league.MaxPlayers, toJSON(league.Waitlist), timePtrValue(league.ArchivedAt), toJSON(league.OwnershipTransfer), toJSON(league.Permissions), league.ClubID, timeValuePtr(league.CreatedAt),
 	)
 	if err != nil {
 		return model.League{}, err
@@ -160,9 +179,12 @@ func (s *PostgresStore) CreateLeague(league model.League) (model.League, error)
 func (s *PostgresStore) UpdateLeague(league model.League) error {
 	adminJSON := toJSON(league.AdminRoles)
 	playerJSON := toJSON(league.PlayerIDs)
+	scoringJSON := toJSON(league.Scoring)
+	tieBreakJSON := toJSON(league.TieBreakers)
+	divisionJSON := toJSON(league.Divisions)
 
-	res, err := s.db.Exec(`UPDATE leagues SET name = $1, description = $2, location = $3, owner_id = $4, admin_roles = $5, player_ids = $6, sets_per_match = $7, start_date = $8, end_date = $9, status = $10, created_at = $11 WHERE id = $12`,
-		league.Name, league.Description, league.Location, league.OwnerID, adminJSON, playerJSON, league.SetsPerMatch, timeValuePtr(league.StartDate), timePtrValue(league.EndDate), string(league.Status), timeValuePtr(league.CreatedAt), league.ID,
+	res, err := s.db.Exec(`UPDATE leagues SET name = $1, description = $2, location = $3, owner_id = $4, admin_roles = $5, player_ids = $6, sets_per_match = $7, points_per_set = $8, scoring = $9, tie_breakers = $10, confirmation_hours = $11, auto_confirm_action = $12, start_date = $13, end_date = $14, status = $15, season = $16, previous_season_id = $17, divisions = $18, promotion_count = $19, doubles = $20, competition = $21, rubbers_per_fixture = $22, teams = $23, handicap = $24, handicaps = $25, withdrawal_policy = $26, withdrawals = $27, visibility = $28, join_code = $29, max_players = $30, waitlist = $31, archived_at = $32, ownership_transfer = $33, permissions = $34, club_id = $35, created_at = $36 WHERE id = $37`,
+		league.Name, league.Description, league.Location, league.OwnerID, adminJSON, playerJSON, league.SetsPerMatch, league.PointsPerSet, scoringJSON, tieBreakJSON, league.ConfirmationHours, string(league.AutoConfirmAction), timeValuePtr(league.StartDate), timePtrValue(league.EndDate), string(league.Status), league.Season, league.PreviousSeasonID, divisionJSON, league.PromotionCount, league.Doubles, string(league.Competition), league.RubbersPerFixture, toJSON(league.Teams), league.Handicap, toJSON(league.Handicaps), string(league.WithdrawalPolicy), toJSON(league.Withdrawals), string(league.Visibility), league.JoinCode, league.MaxPlayers, toJSON(league.Waitlist), timePtrValue(league.ArchivedAt), toJSON(league.OwnershipTransfer), toJSON(league.Permissions), league.ClubID, timeValuePtr(league.CreatedAt), league.ID,
 	)
 	if err != nil {
 		return err
@@ -188,6 +210,17 @@ func (s *PostgresStore) AddPlayerToLeague(leagueID, userID string) error {
 	return s.UpdateLeague(league)
 }
 
+func (s *PostgresStore) RemovePlayerFromLeague(leagueID, userID string) error {
+	league, ok := s.GetLeague(leagueID)
+	if !ok {
+		return errors.New("league not found")
+	}
+	if !containsID(league.PlayerIDs, userID) {
+		return errors.New("player not found")
+	}
+	return s.UpdateLeague(withoutPlayer(league, userID))
+}
+
 func (s *PostgresStore) AddAdminToLeague(leagueID, userID string, role model.LeagueAdminRole) error {
 	league, ok := s.GetLeague(leagueID)
 	if !ok {
@@ -349,7 +382,7 @@ func (s *PostgresStore) HasPendingJoinRequest(leagueID, userID string) bool {
 }
 
 func (s *PostgresStore) ListMatches(leagueID string) []model.Match {
-	rows, err := s.db.Query(`SELECT id, league_id, player_a_id, player_b_id, sets_json, status, reported_by, confirmed_by, created_at FROM matches WHERE league_id = $1`, leagueID)
+	rows, err := s.db.Query(`SELECT `+matchColumns+` FROM matches WHERE league_id = $1`, leagueID)
 	if err != nil {
 		return nil
 	}
@@ -368,7 +401,7 @@ func (s *PostgresStore) ListMatches(leagueID string) []model.Match {
 }
 
 func (s *PostgresStore) GetMatch(id string) (model.Match, bool) {
-	row := s.db.QueryRow(`SELECT id, league_id, player_a_id, player_b_id, sets_json, status, reported_by, confirmed_by, created_at FROM matches WHERE id = $1`, id)
+	row := s.db.QueryRow(`SELECT `+matchColumns+` FROM matches WHERE id = $1`, id)
 	match, err := scanMatchRow(row)
 	if err != nil {
 		return model.Match{}, false
@@ -384,8 +417,8 @@ func (s *PostgresStore) CreateMatch(match model.Match) (model.Match, error) {
 		match.CreatedAt = time.Now()
 	}
 	setsJSON := toJSON(match.Sets)
-	_, err := s.db.Exec(`INSERT INTO matches (id, league_id, player_a_id, player_b_id, sets_json, status, reported_by, confirmed_by, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
-		match.ID, match.LeagueID, match.PlayerAID, match.PlayerBID, setsJSON, string(match.Status), match.ReportedBy, match.ConfirmedBy, timeValuePtr(match.CreatedAt),
+	_, err := s.db.Exec(`INSERT INTO matches (`+matchColumns+`) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24)`,
+		match.ID, match.LeagueID, match.PlayerAID, match.PlayerBID, match.PartnerAID, match.PartnerBID, match.FixtureID, match.Rubber, match.HeadStartA, match.HeadStartB, setsJSON, string(match.Outcome), match.ConcededBy, string(match.Status), match.ReportedBy, match.ConfirmedBy, match.AutoConfirmed, toJSON(match.Dispute), timePtrValue(match.ScheduledAt), match.Venue, toJSON(match.Proposal), match.ClubID, timePtrValue(match.ReportedAt), timeValuePtr(match.CreatedAt),
 	)
 	if err != nil {
 		return model.Match{}, err
@@ -395,8 +428,8 @@ func (s *PostgresStore) CreateMatch(match model.Match) (model.Match, error) {
 
 func (s *PostgresStore) UpdateMatch(match model.Match) error {
 	setsJSON := toJSON(match.Sets)
-	res, err := s.db.Exec(`UPDATE matches SET league_id = $1, player_a_id = $2, player_b_id = $3, sets_json = $4, status = $5, reported_by = $6, confirmed_by = $7, created_at = $8 WHERE id = $9`,
-		match.LeagueID, match.PlayerAID, match.PlayerBID, setsJSON, string(match.Status), match.ReportedBy, match.ConfirmedBy, timeValuePtr(match.CreatedAt), match.ID,
+	res, err := s.db.Exec(`UPDATE matches SET league_id = $1, player_a_id = $2, player_b_id = $3, partner_a_id = $4, partner_b_id = $5, fixture_id = $6, rubber = $7, head_start_a = $8, head_start_b = $9, sets_json = $10, outcome = $11, conceded_by = $12, status = $13, reported_by = $14, confirmed_by = $15, auto_confirmed = $16, dispute = $17, scheduled_at = $18, venue = $19, proposal = $20, club_id = $21, reported_at = $22, created_at = $23 WHERE id = $24`,
+		match.LeagueID, match.PlayerAID, match.PlayerBID, match.PartnerAID, match.PartnerBID, match.FixtureID, match.Rubber, match.HeadStartA, match.HeadStartB, setsJSON, string(match.Outcome), match.ConcededBy, string(match.Status), match.ReportedBy, match.ConfirmedBy, match.AutoConfirmed, toJSON(match.Dispute), timePtrValue(match.ScheduledAt), match.Venue, toJSON(match.Proposal), match.ClubID, timePtrValue(match.ReportedAt), timeValuePtr(match.CreatedAt), match.ID,
 	)
 	if err != nil {
 		return err
@@ -408,8 +441,17 @@ func (s *PostgresStore) UpdateMatch(match model.Match) error {
 	return nil
 }
 
+// ResultsVersion reads the sequence that triggers on the match tables bump.
+func (s *PostgresStore) ResultsVersion() (int64, bool) {
+	var version int64
+	if err := s.db.QueryRow(`SELECT CASE WHEN is_called THEN last_value ELSE 0 END FROM results_version`).Scan(&version); err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
 func (s *PostgresStore) ListFriendlyMatches() []model.FriendlyMatch {
-	rows, err := s.db.Query(`SELECT id, player_a_id, player_b_id, sets_json, status, reported_by, confirmed_by, played_at, created_at FROM friendly_matches`)
+	rows, err := s.db.Query(`SELECT ` + friendlyMatchColumns + ` FROM friendly_matches`)
 	if err != nil {
 		return nil
 	}
@@ -428,7 +470,7 @@ func (s *PostgresStore) ListFriendlyMatches() []model.FriendlyMatch {
 }
 
 func (s *PostgresStore) GetFriendlyMatch(id string) (model.FriendlyMatch, bool) {
-	row := s.db.QueryRow(`SELECT id, player_a_id, player_b_id, sets_json, status, reported_by, confirmed_by, played_at, created_at FROM friendly_matches WHERE id = $1`, id)
+	row := s.db.QueryRow(`SELECT `+friendlyMatchColumns+` FROM friendly_matches WHERE id = $1`, id)
 	match, err := scanFriendlyMatchRow(row)
 	if err != nil {
 		return model.FriendlyMatch{}, false
@@ -447,8 +489,8 @@ func (s *PostgresStore) CreateFriendlyMatch(match model.FriendlyMatch) (model.Fr
 		match.PlayedAt = match.CreatedAt
 	}
 	setsJSON := toJSON(match.Sets)
-	_, err := s.db.Exec(`INSERT INTO friendly_matches (id, player_a_id, player_b_id, sets_json, status, reported_by, confirmed_by, played_at, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
-		match.ID, match.PlayerAID, match.PlayerBID, setsJSON, string(match.Status), match.ReportedBy, match.ConfirmedBy, timeValuePtr(match.PlayedAt), timeValuePtr(match.CreatedAt),
+	_, err := s.db.Exec(`INSERT INTO friendly_matches (`+friendlyMatchColumns+`) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)`,
+		match.ID, match.PlayerAID, match.PlayerBID, match.PartnerAID, match.PartnerBID, setsJSON, string(match.Outcome), match.ConcededBy, string(match.Status), match.ReportedBy, match.ConfirmedBy, match.AutoConfirmed, timeValuePtr(match.PlayedAt), timeValuePtr(match.CreatedAt),
 	)
 	if err != nil {
 		return model.FriendlyMatch{}, err
@@ -458,8 +500,8 @@ func (s *PostgresStore) CreateFriendlyMatch(match model.FriendlyMatch) (model.Fr
 
 func (s *PostgresStore) UpdateFriendlyMatch(match model.FriendlyMatch) error {
 	setsJSON := toJSON(match.Sets)
-	res, err := s.db.Exec(`UPDATE friendly_matches SET player_a_id = $1, player_b_id = $2, sets_json = $3, status = $4, reported_by = $5, confirmed_by = $6, played_at = $7, created_at = $8 WHERE id = $9`,
-		match.PlayerAID, match.PlayerBID, setsJSON, string(match.Status), match.ReportedBy, match.ConfirmedBy, timeValuePtr(match.PlayedAt), timeValuePtr(match.CreatedAt), match.ID,
+	res, err := s.db.Exec(`UPDATE friendly_matches SET player_a_id = $1, player_b_id = $2, partner_a_id = $3, partner_b_id = $4, sets_json = $5, outcome = $6, conceded_by = $7, status = $8, reported_by = $9, confirmed_by = $10, auto_confirmed = $11, played_at = $12, created_at = $13 WHERE id = $14`,
+		match.PlayerAID, match.PlayerBID, match.PartnerAID, match.PartnerBID, setsJSON, string(match.Outcome), match.ConcededBy, string(match.Status), match.ReportedBy, match.ConfirmedBy, match.AutoConfirmed, timeValuePtr(match.PlayedAt), timeValuePtr(match.CreatedAt), match.ID,
 	)
 	if err != nil {
 		return err
@@ -471,6 +513,330 @@ func (s *PostgresStore) UpdateFriendlyMatch(match model.FriendlyMatch) error {
 	return nil
 }
 
+func (s *PostgresStore) ListFixtures(leagueID string) []model.Fixture {
+	rows, err := s.db.Query(`SELECT `+fixtureColumns+` FROM fixtures WHERE league_id = $1 ORDER BY play_at ASC`, leagueID)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	fixtures := []model.Fixture{}
+	for rows.Next() {
+		fixture, err := scanFixtureRow(rows)
+		if err != nil {
+			continue
+		}
+		fixtures = append(fixtures, fixture)
+	}
+	return fixtures
+}
+
+func (s *PostgresStore) GetFixture(id string) (model.Fixture, bool) {
+	fixture, err := scanFixtureRow(s.db.QueryRow(`SELECT `+fixtureColumns+` FROM fixtures WHERE id = $1`, id))
+	if err != nil {
+		return model.Fixture{}, false
+	}
+	return fixture, true
+}
+
+func (s *PostgresStore) CreateFixture(fixture model.Fixture) (model.Fixture, error) {
+	if fixture.ID == "" {
+		fixture.ID = uuid.NewString()
+	}
+	if fixture.CreatedAt.IsZero() {
+		fixture.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO fixtures (`+fixtureColumns+`) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		fixture.ID, fixture.LeagueID, fixture.HomeTeamID, fixture.AwayTeamID, timeValuePtr(fixture.PlayAt), toJSON(fixture.HomeOrder), toJSON(fixture.AwayOrder), timeValuePtr(fixture.CreatedAt),
+	)
+	if err != nil {
+		return model.Fixture{}, err
+	}
+	return fixture, nil
+}
+
+func (s *PostgresStore) UpdateFixture(fixture model.Fixture) error {
+	res, err := s.db.Exec(`UPDATE fixtures SET home_team_id = $1, away_team_id = $2, play_at = $3, home_order = $4, away_order = $5 WHERE id = $6`,
+		fixture.HomeTeamID, fixture.AwayTeamID, timeValuePtr(fixture.PlayAt), toJSON(fixture.HomeOrder), toJSON(fixture.AwayOrder), fixture.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return errors.New("fixture not found")
+	}
+	return nil
+}
+
+func (s *PostgresStore) CreateLeagueInvite(invite model.LeagueInvite) (model.LeagueInvite, error) {
+	if invite.ID == "" {
+		invite.ID = uuid.NewString()
+	}
+	if invite.CreatedAt.IsZero() {
+		invite.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO league_invites (`+inviteColumns+`) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		invite.ID, invite.LeagueID, invite.Token, invite.CreatedBy, timeValuePtr(invite.ExpiresAt), invite.Revoked, invite.Uses, timeValuePtr(invite.CreatedAt),
+	)
+	if err != nil {
+		return model.LeagueInvite{}, err
+	}
+	return invite, nil
+}
+
+func (s *PostgresStore) ListLeagueInvites(leagueID string) []model.LeagueInvite {
+	rows, err := s.db.Query(`SELECT `+inviteColumns+` FROM league_invites WHERE league_id = $1 ORDER BY created_at DESC`, leagueID)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	invites := []model.LeagueInvite{}
+	for rows.Next() {
+		invite, err := scanInviteRow(rows)
+		if err != nil {
+			continue
+		}
+		invites = append(invites, invite)
+	}
+	return invites
+}
+
+func (s *PostgresStore) GetLeagueInviteByToken(token string) (model.LeagueInvite, bool) {
+	invite, err := scanInviteRow(s.db.QueryRow(`SELECT `+inviteColumns+` FROM league_invites WHERE token = $1`, token))
+	if err != nil {
+		return model.LeagueInvite{}, false
+	}
+	return invite, true
+}
+
+func (s *PostgresStore) UpdateLeagueInvite(invite model.LeagueInvite) error {
+	res, err := s.db.Exec(`UPDATE league_invites SET expires_at = $1, revoked = $2, uses = $3 WHERE id = $4`,
+		timeValuePtr(invite.ExpiresAt), invite.Revoked, invite.Uses, invite.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return errors.New("invite not found")
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetCalendarFeed(userID, leagueID string) (model.CalendarFeed, bool) {
+	feed, err := scanCalendarFeedRow(s.db.QueryRow(`SELECT `+calendarFeedColumns+` FROM calendar_feeds WHERE user_id = $1 AND league_id = $2`, userID, leagueID))
+	if err != nil {
+		return model.CalendarFeed{}, false
+	}
+	return feed, true
+}
+
+func (s *PostgresStore) GetCalendarFeedByToken(token string) (model.CalendarFeed, bool) {
+	feed, err := scanCalendarFeedRow(s.db.QueryRow(`SELECT `+calendarFeedColumns+` FROM calendar_feeds WHERE token = $1`, token))
+	if err != nil {
+		return model.CalendarFeed{}, false
+	}
+	return feed, true
+}
+
+func (s *PostgresStore) SaveCalendarFeed(feed model.CalendarFeed) (model.CalendarFeed, error) {
+	if feed.ID == "" {
+		feed.ID = uuid.NewString()
+	}
+	if feed.CreatedAt.IsZero() {
+		feed.CreatedAt = time.Now()
+	}
+	row := s.db.QueryRow(`INSERT INTO calendar_feeds (`+calendarFeedColumns+`) VALUES ($1,$2,$3,$4,$5)
+		ON CONFLICT (user_id, league_id) DO UPDATE SET token = EXCLUDED.token
+		RETURNING `+calendarFeedColumns,
+		feed.ID, feed.UserID, feed.LeagueID, feed.Token, timeValuePtr(feed.CreatedAt),
+	)
+	return scanCalendarFeedRow(row)
+}
+
+func (s *PostgresStore) ListClubs() []model.Club {
+	rows, err := s.db.Query(`SELECT ` + clubColumns + ` FROM clubs ORDER BY name`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	clubs := []model.Club{}
+	for rows.Next() {
+		club, err := scanClubRow(rows)
+		if err != nil {
+			continue
+		}
+		clubs = append(clubs, club)
+	}
+	return clubs
+}
+
+func (s *PostgresStore) GetClub(id string) (model.Club, bool) {
+	club, err := scanClubRow(s.db.QueryRow(`SELECT `+clubColumns+` FROM clubs WHERE id = $1`, id))
+	if err != nil {
+		return model.Club{}, false
+	}
+	return club, true
+}
+
+func (s *PostgresStore) CreateClub(club model.Club) (model.Club, error) {
+	if club.ID == "" {
+		club.ID = uuid.NewString()
+	}
+	if club.CreatedAt.IsZero() {
+		club.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO clubs (`+clubColumns+`) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+		club.ID, club.Name, club.Address, club.Latitude, club.Longitude, toJSON(club.Courts), toJSON(club.OpeningHours), toJSON(club.AdminIDs), club.CreatedBy, timeValuePtr(club.CreatedAt),
+	)
+	if err != nil {
+		return model.Club{}, err
+	}
+	return club, nil
+}
+
+func (s *PostgresStore) UpdateClub(club model.Club) error {
+	res, err := s.db.Exec(`UPDATE clubs SET name = $1, address = $2, latitude = $3, longitude = $4, courts = $5, opening_hours = $6, admin_ids = $7 WHERE id = $8`,
+		club.Name, club.Address, club.Latitude, club.Longitude, toJSON(club.Courts), toJSON(club.OpeningHours), toJSON(club.AdminIDs), club.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return errors.New("club not found")
+	}
+	return nil
+}
+
+func (s *PostgresStore) ListCourtBookings(clubID string) []model.CourtBooking {
+	rows, err := s.db.Query(`SELECT `+courtBookingColumns+` FROM court_bookings WHERE club_id = $1 ORDER BY start_at`, clubID)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	bookings := []model.CourtBooking{}
+	for rows.Next() {
+		booking, err := scanCourtBookingRow(rows)
+		if err != nil {
+			continue
+		}
+		bookings = append(bookings, booking)
+	}
+	return bookings
+}
+
+func (s *PostgresStore) GetCourtBooking(id string) (model.CourtBooking, bool) {
+	booking, err := scanCourtBookingRow(s.db.QueryRow(`SELECT `+courtBookingColumns+` FROM court_bookings WHERE id = $1`, id))
+	if err != nil {
+		return model.CourtBooking{}, false
+	}
+	return booking, true
+}
+
+func (s *PostgresStore) CreateCourtBooking(booking model.CourtBooking) (model.CourtBooking, error) {
+	if booking.ID == "" {
+		booking.ID = uuid.NewString()
+	}
+	if booking.CreatedAt.IsZero() {
+		booking.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO court_bookings (`+courtBookingColumns+`) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+		booking.ID, booking.ClubID, booking.CourtID, booking.UserID, booking.MatchID, timeValuePtr(booking.Start), timeValuePtr(booking.End), timePtrValue(booking.CancelledAt), timeValuePtr(booking.CreatedAt),
+	)
+	if err != nil {
+		return model.CourtBooking{}, err
+	}
+	return booking, nil
+}
+
+func (s *PostgresStore) UpdateCourtBooking(booking model.CourtBooking) error {
+	res, err := s.db.Exec(`UPDATE court_bookings SET match_id = $1, start_at = $2, end_at = $3, cancelled_at = $4 WHERE id = $5`,
+		booking.MatchID, timeValuePtr(booking.Start), timeValuePtr(booking.End), timePtrValue(booking.CancelledAt), booking.ID,
+	)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return errors.New("booking not found")
+	}
+	return nil
+}
+
+func (s *PostgresStore) GetStandingsSnapshot(leagueID string) (model.StandingsSnapshot, bool) {
+	var snapshot model.StandingsSnapshot
+	var entriesJSON, awardsJSON []byte
+	err := s.db.QueryRow(`SELECT league_id, entries, awards, created_at FROM league_standings_snapshots WHERE league_id = $1`, leagueID).
+		Scan(&snapshot.LeagueID, &entriesJSON, &awardsJSON, &snapshot.CreatedAt)
+	if err != nil {
+		return model.StandingsSnapshot{}, false
+	}
+	_ = json.Unmarshal(entriesJSON, &snapshot.Entries)
+	if len(awardsJSON) > 0 {
+		_ = json.Unmarshal(awardsJSON, &snapshot.Awards)
+	}
+	return snapshot, true
+}
+
+func (s *PostgresStore) CreateStandingsSnapshot(snapshot model.StandingsSnapshot) (model.StandingsSnapshot, error) {
+	if snapshot.CreatedAt.IsZero() {
+		snapshot.CreatedAt = time.Now()
+	}
+	_, err := s.db.Exec(`INSERT INTO league_standings_snapshots (league_id, entries, awards, created_at) VALUES ($1,$2,$3,$4)`,
+		snapshot.LeagueID, toJSON(snapshot.Entries), toJSON(snapshot.Awards), timeValuePtr(snapshot.CreatedAt),
+	)
+	if err != nil {
+		return model.StandingsSnapshot{}, err
+	}
+	return snapshot, nil
+}
+
+func (s *PostgresStore) ListMatchRevisions(matchID string) []model.MatchRevision {
+	rows, err := s.db.Query(`SELECT id, match_id, action, sets_json, outcome, conceded_by, status, changed_by, reason, created_at FROM match_revisions WHERE match_id=$1 ORDER BY created_at ASC`, matchID)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	revisions := []model.MatchRevision{}
+	for rows.Next() {
+		var revision model.MatchRevision
+		var setsJSON []byte
+		var action, outcome, status string
+		if err := rows.Scan(&revision.ID, &revision.MatchID, &action, &setsJSON, &outcome, &revision.ConcededBy, &status, &revision.ChangedBy, &revision.Reason, &revision.CreatedAt); err != nil {
+			continue
+		}
+		_ = json.Unmarshal(setsJSON, &revision.Sets)
+		revision.Action = model.MatchRevisionAction(action)
+		revision.Outcome = model.MatchOutcome(outcome)
+		revision.Status = model.MatchStatus(status)
+		revisions = append(revisions, revision)
+	}
+	return revisions
+}
+
+func (s *PostgresStore) CreateMatchRevision(revision model.MatchRevision) (model.MatchRevision, error) {
+	if revision.ID == "" {
+		revision.ID = uuid.NewString()
+	}
+	if revision.CreatedAt.IsZero() {
+		revision.CreatedAt = time.Now()
+	}
+	setsJSON := toJSON(revision.Sets)
+	_, err := s.db.Exec(`INSERT INTO match_revisions (id, match_id, action, sets_json, outcome, conceded_by, status, changed_by, reason, created_at) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+		revision.ID, revision.MatchID, string(revision.Action), setsJSON, string(revision.Outcome), revision.ConcededBy, string(revision.Status), revision.ChangedBy, revision.Reason, timeValuePtr(revision.CreatedAt),
+	)
+	if err != nil {
+		return model.MatchRevision{}, err
+	}
+	return revision, nil
+}
+
 func (s *PostgresStore) ListReports() []model.Report {
 	rows, err := s.db.Query(`SELECT id, user_id, type, title, description, status, created_at FROM reports ORDER BY created_at DESC`)
 	if err != nil {
@@ -510,9 +876,9 @@ func (s *PostgresStore) CreateReport(report model.Report) (model.Report, error)
 
 func scanLeagueRow(scanner interface{ Scan(dest ...any) error }) (model.League, error) {
 	var league model.League
-	var adminJSON, playerJSON []byte
-	var startDate, endDate, createdAt sql.NullTime
-	var status string
+	var adminJSON, playerJSON, scoringJSON, tieBreakJSON, divisionJSON, teamJSON, handicapJSON, withdrawalJSON, waitlistJSON, transferJSON, permissionsJSON []byte
+	var startDate, endDate, archivedAt, createdAt sql.NullTime
+	var status, autoConfirmAction, competition, withdrawalPolicy, visibility string
 	if err := scanner.Scan(
 		&league.ID,
 		&league.Name,
@@ -522,14 +888,43 @@ func scanLeagueRow(scanner interface{ Scan(dest ...any) error }) (model.League,
 		&adminJSON,
 		&playerJSON,
 		&league.SetsPerMatch,
+		&league.PointsPerSet,
+		&scoringJSON,
+		&tieBreakJSON,
+		&league.ConfirmationHours,
+		&autoConfirmAction,
 		&startDate,
 		&endDate,
 		&status,
+		&league.Season,
+		&league.PreviousSeasonID,
+		&divisionJSON,
+		&league.PromotionCount,
+		&league.Doubles,
+		&competition,
+		&league.RubbersPerFixture,
+		&teamJSON,
+		&league.Handicap,
+		&handicapJSON,
+		&withdrawalPolicy,
+		&withdrawalJSON,
+		&visibility,
+		&league.JoinCode,
+		&league.MaxPlayers,
+		&waitlistJSON,
+		&archivedAt,
+		&transferJSON,
+		&permissionsJSON,
+		&league.ClubID,
 		&createdAt,
 	); err != nil {
 		return model.League{}, err
 	}
 	league.Status = model.LeagueStatus(status)
+	league.AutoConfirmAction = model.AutoConfirmAction(autoConfirmAction)
+	league.Competition = model.CompetitionType(competition)
+	league.WithdrawalPolicy = model.WithdrawalPolicy(withdrawalPolicy)
+	league.Visibility = model.LeagueVisibility(visibility)
 	if startDate.Valid {
 		league.StartDate = startDate.Time
 	}
@@ -537,6 +932,10 @@ func scanLeagueRow(scanner interface{ Scan(dest ...any) error }) (model.League,
 		parsed := endDate.Time
 		league.EndDate = &parsed
 	}
+	if archivedAt.Valid {
+		archived := archivedAt.Time
+		league.ArchivedAt = &archived
+	}
 	if createdAt.Valid {
 		league.CreatedAt = createdAt.Time
 	}
@@ -546,6 +945,33 @@ func scanLeagueRow(scanner interface{ Scan(dest ...any) error }) (model.League,
 	if len(playerJSON) > 0 {
 		_ = json.Unmarshal(playerJSON, &league.PlayerIDs)
 	}
+	if len(scoringJSON) > 0 {
+		_ = json.Unmarshal(scoringJSON, &league.Scoring)
+	}
+	if len(teamJSON) > 0 {
+		_ = json.Unmarshal(teamJSON, &league.Teams)
+	}
+	if len(divisionJSON) > 0 {
+		_ = json.Unmarshal(divisionJSON, &league.Divisions)
+	}
+	if len(handicapJSON) > 0 {
+		_ = json.Unmarshal(handicapJSON, &league.Handicaps)
+	}
+	if len(withdrawalJSON) > 0 {
+		_ = json.Unmarshal(withdrawalJSON, &league.Withdrawals)
+	}
+	if len(waitlistJSON) > 0 {
+		_ = json.Unmarshal(waitlistJSON, &league.Waitlist)
+	}
+	if len(transferJSON) > 0 {
+		_ = json.Unmarshal(transferJSON, &league.OwnershipTransfer)
+	}
+	if len(permissionsJSON) > 0 {
+		_ = json.Unmarshal(permissionsJSON, &league.Permissions)
+	}
+	if len(tieBreakJSON) > 0 {
+		_ = json.Unmarshal(tieBreakJSON, &league.TieBreakers)
+	}
 	if league.AdminRoles == nil {
 		league.AdminRoles = map[string]model.LeagueAdminRole{}
 	}
@@ -554,51 +980,116 @@ func scanLeagueRow(scanner interface{ Scan(dest ...any) error }) (model.League,
 
 func scanMatchRow(scanner interface{ Scan(dest ...any) error }) (model.Match, error) {
 	var match model.Match
-	var setsJSON []byte
-	var createdAt sql.NullTime
-	var status string
+	var setsJSON, disputeJSON, proposalJSON []byte
+	var scheduledAt, reportedAt, createdAt sql.NullTime
+	var status, outcome string
 	if err := scanner.Scan(
 		&match.ID,
 		&match.LeagueID,
 		&match.PlayerAID,
 		&match.PlayerBID,
+		&match.PartnerAID,
+		&match.PartnerBID,
+		&match.FixtureID,
+		&match.Rubber,
+		&match.HeadStartA,
+		&match.HeadStartB,
 		&setsJSON,
+		&outcome,
+		&match.ConcededBy,
 		&status,
 		&match.ReportedBy,
 		&match.ConfirmedBy,
+		&match.AutoConfirmed,
+		&disputeJSON,
+		&scheduledAt,
+		&match.Venue,
+		&proposalJSON,
+		&match.ClubID,
+		&reportedAt,
 		&createdAt,
 	); err != nil {
 		return model.Match{}, err
 	}
 	match.Status = model.MatchStatus(status)
+	if scheduledAt.Valid {
+		match.ScheduledAt = &scheduledAt.Time
+	}
+	if reportedAt.Valid {
+		match.ReportedAt = &reportedAt.Time
+	}
+	match.Outcome = model.MatchOutcome(outcome)
 	if createdAt.Valid {
 		match.CreatedAt = createdAt.Time
 	}
 	if len(setsJSON) > 0 {
 		_ = json.Unmarshal(setsJSON, &match.Sets)
 	}
+	if len(disputeJSON) > 0 {
+		_ = json.Unmarshal(disputeJSON, &match.Dispute)
+	}
+	if len(proposalJSON) > 0 {
+		_ = json.Unmarshal(proposalJSON, &match.Proposal)
+	}
 	return match, nil
 }
 
+func scanFixtureRow(scanner interface{ Scan(dest ...any) error }) (model.Fixture, error) {
+	var fixture model.Fixture
+	var homeJSON, awayJSON []byte
+	var playAt, createdAt sql.NullTime
+	if err := scanner.Scan(
+		&fixture.ID,
+		&fixture.LeagueID,
+		&fixture.HomeTeamID,
+		&fixture.AwayTeamID,
+		&playAt,
+		&homeJSON,
+		&awayJSON,
+		&createdAt,
+	); err != nil {
+		return model.Fixture{}, err
+	}
+	if playAt.Valid {
+		fixture.PlayAt = playAt.Time
+	}
+	if createdAt.Valid {
+		fixture.CreatedAt = createdAt.Time
+	}
+	if len(homeJSON) > 0 {
+		_ = json.Unmarshal(homeJSON, &fixture.HomeOrder)
+	}
+	if len(awayJSON) > 0 {
+		_ = json.Unmarshal(awayJSON, &fixture.AwayOrder)
+	}
+	return fixture, nil
+}
+
 func scanFriendlyMatchRow(scanner interface{ Scan(dest ...any) error }) (model.FriendlyMatch, error) {
 	var match model.FriendlyMatch
 	var setsJSON []byte
 	var playedAt, createdAt sql.NullTime
-	var status string
+	var status, outcome string
 	if err := scanner.Scan(
 		&match.ID,
 		&match.PlayerAID,
 		&match.PlayerBID,
+		&match.PartnerAID,
+		&match.PartnerBID,
 		&setsJSON,
+		&outcome,
+		&match.ConcededBy,
 		&status,
 		&match.ReportedBy,
 		&match.ConfirmedBy,
+		&match.AutoConfirmed,
 		&playedAt,
 		&createdAt,
 	); err != nil {
 		return model.FriendlyMatch{}, err
 	}
 	match.Status = model.MatchStatus(status)
+	match.Outcome = model.MatchOutcome(outcome)
 	if playedAt.Valid {
 		match.PlayedAt = playedAt.Time
 	}
@@ -635,3 +1126,104 @@ func toJSON(v any) []byte {
 	}
 	return data
 }
+
+func scanCalendarFeedRow(scanner interface{ Scan(dest ...any) error }) (model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	var createdAt sql.NullTime
+	if err := scanner.Scan(&feed.ID, &feed.UserID, &feed.LeagueID, &feed.Token, &createdAt); err != nil {
+		return model.CalendarFeed{}, err
+	}
+	if createdAt.Valid {
+		feed.CreatedAt = createdAt.Time
+	}
+	return feed, nil
+}
+
+func scanCourtBookingRow(scanner interface{ Scan(dest ...any) error }) (model.CourtBooking, error) {
+	var booking model.CourtBooking
+	var startAt, endAt, cancelledAt, createdAt sql.NullTime
+	if err := scanner.Scan(
+		&booking.ID,
+		&booking.ClubID,
+		&booking.CourtID,
+		&booking.UserID,
+		&booking.MatchID,
+		&startAt,
+		&endAt,
+		&cancelledAt,
+		&createdAt,
+	); err != nil {
+		return model.CourtBooking{}, err
+	}
+	if startAt.Valid {
+		booking.Start = startAt.Time
+	}
+	if endAt.Valid {
+		booking.End = endAt.Time
+	}
+	if cancelledAt.Valid {
+		cancelled := cancelledAt.Time
+		booking.CancelledAt = &cancelled
+	}
+	if createdAt.Valid {
+		booking.CreatedAt = createdAt.Time
+	}
+	return booking, nil
+}
+
+func scanClubRow(scanner interface{ Scan(dest ...any) error }) (model.Club, error) {
+	var club model.Club
+	var courtsJSON, hoursJSON, adminJSON []byte
+	var createdAt sql.NullTime
+	if err := scanner.Scan(
+		&club.ID,
+		&club.Name,
+		&club.Address,
+		&club.Latitude,
+		&club.Longitude,
+		&courtsJSON,
+		&hoursJSON,
+		&adminJSON,
+		&club.CreatedBy,
+		&createdAt,
+	); err != nil {
+		return model.Club{}, err
+	}
+	if createdAt.Valid {
+		club.CreatedAt = createdAt.Time
+	}
+	if len(courtsJSON) > 0 {
+		_ = json.Unmarshal(courtsJSON, &club.Courts)
+	}
+	if len(hoursJSON) > 0 {
+		_ = json.Unmarshal(hoursJSON, &club.OpeningHours)
+	}
+	if len(adminJSON) > 0 {
+		_ = json.Unmarshal(adminJSON, &club.AdminIDs)
+	}
+	return club, nil
+}
+
+func scanInviteRow(scanner interface{ Scan(dest ...any) error }) (model.LeagueInvite, error) {
+	var invite model.LeagueInvite
+	var expiresAt, createdAt sql.NullTime
+	if err := scanner.Scan(
+		&invite.ID,
+		&invite.LeagueID,
+		&invite.Token,
+		&invite.CreatedBy,
+		&expiresAt,
+		&invite.Revoked,
+		&invite.Uses,
+		&createdAt,
+	); err != nil {
+		return model.LeagueInvite{}, err
+	}
+	if expiresAt.Valid {
+		invite.ExpiresAt = expiresAt.Time
+	}
+	if createdAt.Valid {
+		invite.CreatedAt = createdAt.Time
+	}
+	return invite, nil
+}
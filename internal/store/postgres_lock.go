@@ -0,0 +1,52 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TryLock takes a session-level Postgres advisory lock keyed by name, so only
+// one app instance runs a given background job at a time. The lock lives on a
+// dedicated connection that is released by the returned unlock func.
+func (s *PostgresStore) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("advisory lock conn: %w", err)
+	}
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, name).Scan(&locked); err != nil {
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("advisory lock: %w", err)
+	}
+	if !locked {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, name)
+		_ = conn.Close()
+	}, true, nil
+}
+
+// LastRun reads when the job last started on any instance.
+func (s *PostgresStore) LastRun(ctx context.Context, name string) (time.Time, bool, error) {
+	var at time.Time
+	err := s.db.QueryRowContext(ctx, `SELECT last_run_at FROM job_runs WHERE name = $1`, name).Scan(&at)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return at, true, nil
+}
+
+func (s *PostgresStore) RecordRun(ctx context.Context, name string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO job_runs (name, last_run_at) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET last_run_at = EXCLUDED.last_run_at`, name, at)
+	return err
+}
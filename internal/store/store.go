@@ -13,6 +13,7 @@ type Store interface {
 	CreateLeague(league model.League) (model.League, error)
 	UpdateLeague(league model.League) error
 	AddPlayerToLeague(leagueID, userID string) error
+	RemovePlayerFromLeague(leagueID, userID string) error
 	AddAdminToLeague(leagueID, userID string, role model.LeagueAdminRole) error
 	UpdateAdminRole(leagueID, userID string, role model.LeagueAdminRole) error
 	RemoveAdminFromLeague(leagueID, userID string) error
@@ -21,16 +22,44 @@ type Store interface {
 	GetJoinRequest(id string) (model.LeagueJoinRequest, bool)
 	UpdateJoinRequest(request model.LeagueJoinRequest) error
 	HasPendingJoinRequest(leagueID, userID string) bool
+	CreateLeagueInvite(invite model.LeagueInvite) (model.LeagueInvite, error)
+	ListLeagueInvites(leagueID string) []model.LeagueInvite
+	GetLeagueInviteByToken(token string) (model.LeagueInvite, bool)
+	UpdateLeagueInvite(invite model.LeagueInvite) error
+	GetCalendarFeed(userID, leagueID string) (model.CalendarFeed, bool)
+	GetCalendarFeedByToken(token string) (model.CalendarFeed, bool)
+	SaveCalendarFeed(feed model.CalendarFeed) (model.CalendarFeed, error)
+	ListClubs() []model.Club
+	GetClub(id string) (model.Club, bool)
+	CreateClub(club model.Club) (model.Club, error)
+	UpdateClub(club model.Club) error
+	ListCourtBookings(clubID string) []model.CourtBooking
+	GetCourtBooking(id string) (model.CourtBooking, bool)
+	CreateCourtBooking(booking model.CourtBooking) (model.CourtBooking, error)
+	UpdateCourtBooking(booking model.CourtBooking) error
+	GetStandingsSnapshot(leagueID string) (model.StandingsSnapshot, bool)
+	CreateStandingsSnapshot(snapshot model.StandingsSnapshot) (model.StandingsSnapshot, error)
 
 	ListMatches(leagueID string) []model.Match
 	GetMatch(id string) (model.Match, bool)
 	CreateMatch(match model.Match) (model.Match, error)
 	UpdateMatch(match model.Match) error
+	ListMatchRevisions(matchID string) []model.MatchRevision
+	CreateMatchRevision(revision model.MatchRevision) (model.MatchRevision, error)
+
+	ListFixtures(leagueID string) []model.Fixture
+	GetFixture(id string) (model.Fixture, bool)
+	CreateFixture(fixture model.Fixture) (model.Fixture, error)
+	UpdateFixture(fixture model.Fixture) error
 
 	ListFriendlyMatches() []model.FriendlyMatch
 	GetFriendlyMatch(id string) (model.FriendlyMatch, bool)
 	CreateFriendlyMatch(match model.FriendlyMatch) (model.FriendlyMatch, error)
 	UpdateFriendlyMatch(match model.FriendlyMatch) error
+	// ResultsVersion changes whenever a league or friendly match is written,
+	// so derived data such as ratings can be cached until it does. ok is false
+	// when the version cannot be read.
+	ResultsVersion() (version int64, ok bool)
 	ListReports() []model.Report
 	CreateReport(report model.Report) (model.Report, error)
 }
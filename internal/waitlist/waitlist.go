@@ -0,0 +1,87 @@
+// Package waitlist hands free slots in capped leagues to waiting players in
+// order. A player offered a slot has OfferWindow to accept it before it moves
+// on to the next one in line.
+package waitlist
+
+import (
+	"errors"
+	"time"
+
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/store"
+)
+
+const OfferWindow = 48 * time.Hour
+
+type Result struct {
+	Offered int
+	Expired int
+}
+
+// Refresh drops lapsed offers and offers open slots to the next waiting
+// players. It reports whether the league changed.
+func Refresh(league *model.League, now time.Time) (Result, bool) {
+	result := Result{}
+	kept := make([]model.WaitlistEntry, 0, len(league.Waitlist))
+	for _, entry := range league.Waitlist {
+		if entry.Offered() && !now.Before(*entry.OfferExpiresAt) {
+			result.Expired++
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	league.Waitlist = kept
+	open := league.OpenSlots()
+	for i := range league.Waitlist {
+		if open == 0 {
+			break
+		}
+		if league.Waitlist[i].Offered() {
+			continue
+		}
+		deadline := now.Add(OfferWindow)
+		league.Waitlist[i].OfferExpiresAt = &deadline
+		result.Offered++
+		open--
+	}
+	return result, result.Offered+result.Expired > 0
+}
+
+// Add puts the player at the end of the line unless they are already on it.
+func Add(league *model.League, userID string, now time.Time) {
+	if _, _, ok := league.WaitlistEntry(userID); ok {
+		return
+	}
+	league.Waitlist = append(league.Waitlist, model.WaitlistEntry{UserID: userID, CreatedAt: now})
+}
+
+func Remove(league *model.League, userID string) {
+	kept := make([]model.WaitlistEntry, 0, len(league.Waitlist))
+	for _, entry := range league.Waitlist {
+		if entry.UserID != userID {
+			kept = append(kept, entry)
+		}
+	}
+	league.Waitlist = kept
+}
+
+func Run(st store.Store, now time.Time) (Result, error) {
+	total := Result{}
+	var errs []error
+	for _, league := range st.ListLeagues() {
+		if league.MaxPlayers <= 0 || len(league.Waitlist) == 0 {
+			continue
+		}
+		result, changed := Refresh(&league, now)
+		if !changed {
+			continue
+		}
+		if err := st.UpdateLeague(league); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		total.Offered += result.Offered
+		total.Expired += result.Expired
+	}
+	return total, errors.Join(errs...)
+}
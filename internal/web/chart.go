@@ -0,0 +1,140 @@
+package web
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Line charts are drawn as SVG on the server, in a fixed viewBox that the
+// page scales to its width.
+const (
+	chartWidth  = 640
+	chartHeight = 220
+	chartLeft   = 48
+	chartRight  = 16
+	chartTop    = 12
+	chartBottom = 28
+	chartYTicks = 5
+)
+
+type chartPoint struct {
+	At    time.Time
+	Value float64
+	// Low and High outline a band around the line, such as the rating
+	// deviation. They are ignored when equal.
+	Low  float64
+	High float64
+}
+
+func ratingChart(progress PlayerProgress) ChartView {
+	points := make([]chartPoint, 0, len(progress.RatingHistory))
+	for _, point := range progress.RatingHistory {
+		points = append(points, chartPoint{At: point.At, Value: point.Rating, Low: point.Rating - point.Deviation, High: point.Rating + point.Deviation})
+	}
+	minY, maxY := chartRange(points, 50)
+	return lineChart("Ranking", points, minY, maxY, "%.0f")
+}
+
+func winRateChart(progress PlayerProgress) ChartView {
+	points := make([]chartPoint, 0, len(progress.Games))
+	for _, game := range progress.Games {
+		points = append(points, chartPoint{At: game.PlayedAt, Value: game.WinRate})
+	}
+	return lineChart("Procent zwycięstw", points, 0, 100, "%.0f%%")
+}
+
+func pointsPerSetChart(progress PlayerProgress) ChartView {
+	points := make([]chartPoint, 0, len(progress.Games))
+	for _, game := range progress.Games {
+		points = append(points, chartPoint{At: game.PlayedAt, Value: game.PointsPerSet})
+	}
+	minY, maxY := chartRange(points, 1)
+	return lineChart("Punkty na set", points, minY, maxY, "%.1f")
+}
+
+// chartRange rounds the spread of the values and their bands out to whole
+// steps.
+func chartRange(points []chartPoint, step float64) (float64, float64) {
+	if len(points) == 0 {
+		return 0, step
+	}
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, point := range points {
+		low, high = math.Min(low, point.Value), math.Max(high, point.Value)
+		if point.High > point.Low {
+			low, high = math.Min(low, point.Low), math.Max(high, point.High)
+		}
+	}
+	if high-low < step {
+		low, high = low-step/2, high+step/2
+	}
+	low, high = math.Floor(low/step)*step, math.Ceil(high/step)*step
+	// Stretch the top so every grid line falls on a whole step.
+	intervals := float64(chartYTicks - 1)
+	return low, low + math.Ceil((high-low)/step/intervals)*step*intervals
+}
+
+func lineChart(title string, points []chartPoint, minY, maxY float64, labelFormat string) ChartView {
+	chart := ChartView{
+		Title:     title,
+		Width:     chartWidth,
+		Height:    chartHeight,
+		PlotLeft:  chartLeft,
+		PlotRight: chartWidth - chartRight,
+		PlotTop:   chartTop,
+		PlotBase:  chartHeight - chartBottom,
+	}
+	if len(points) == 0 {
+		chart.Empty = true
+		return chart
+	}
+
+	first, last := points[0].At, points[len(points)-1].At
+	plotWidth := float64(chartWidth - chartLeft - chartRight)
+	plotHeight := float64(chartHeight - chartTop - chartBottom)
+	x := func(at time.Time) float64 {
+		if !last.After(first) {
+			return chartLeft + plotWidth/2
+		}
+		return round1(chartLeft + plotWidth*float64(at.Sub(first))/float64(last.Sub(first)))
+	}
+	y := func(value float64) float64 {
+		value = math.Max(minY, math.Min(maxY, value))
+		return round1(chartTop + plotHeight*(maxY-value)/(maxY-minY))
+	}
+
+	line := make([]string, 0, len(points))
+	upper := make([]string, 0, len(points))
+	lower := make([]string, 0, len(points))
+	banded := false
+	for _, point := range points {
+		line = append(line, fmt.Sprintf("%g,%g", x(point.At), y(point.Value)))
+		upper = append(upper, fmt.Sprintf("%g,%g", x(point.At), y(point.High)))
+		lower = append(lower, fmt.Sprintf("%g,%g", x(point.At), y(point.Low)))
+		banded = banded || point.High > point.Low
+	}
+	chart.Line = strings.Join(line, " ")
+	if banded && len(points) > 1 {
+		slices.Reverse(lower)
+		chart.Band = strings.Join(append(upper, lower...), " ")
+	}
+	if len(points) == 1 {
+		chart.Dot = &ChartTick{X: x(first), Y: y(points[0].Value)}
+	}
+
+	for i := 0; i < chartYTicks; i++ {
+		value := minY + (maxY-minY)*float64(i)/float64(chartYTicks-1)
+		chart.YTicks = append(chart.YTicks, ChartTick{X: chartLeft - 6, Y: y(value), Label: fmt.Sprintf(labelFormat, value)})
+	}
+	labels := []time.Time{first}
+	if last.After(first) {
+		labels = append(labels, first.Add(last.Sub(first)/2), last)
+	}
+	for _, at := range labels {
+		chart.XTicks = append(chart.XTicks, ChartTick{X: x(at), Y: chartHeight - 8, Label: at.Format("02.01.06")})
+	}
+	return chart
+}
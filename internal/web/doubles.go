@@ -0,0 +1,105 @@
+package web
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"sqoush-app/internal/model"
+)
+
+// sideUser describes a match side as a single user. A doubles pair becomes a
+// synthetic user keyed by pairKey, so pair tables reuse BuildStandings.
+func (s *Server) sideUser(playerIDs []string) model.User {
+	if len(playerIDs) == 1 {
+		user, _ := s.store.GetUser(playerIDs[0])
+		return user
+	}
+	users := s.usersByID(playerIDs)
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.FullName())
+	}
+	sort.Strings(names)
+	return model.User{ID: pairKey(playerIDs), FirstName: strings.Join(names, " / ")}
+}
+
+// concededUser names the side that conceded, which is a pair in doubles.
+func (s *Server) concededUser(match model.Match, concededBy string) model.User {
+	if concededBy == match.PlayerBID {
+		return s.sideUser(match.SideB())
+	}
+	return s.sideUser(match.SideA())
+}
+
+// IsPair tells a pair row of a doubles table from a player row.
+func (e StandingEntry) IsPair() bool {
+	return strings.Contains(e.Player.ID, "+")
+}
+
+func pairKey(playerIDs []string) string {
+	ids := append([]string{}, playerIDs...)
+	sort.Strings(ids)
+	return strings.Join(ids, "+")
+}
+
+// pairStandings ranks every pair that played a doubles match in the league.
+func (s *Server) pairStandings(matches []model.Match, opts StandingsOptions) []StandingEntry {
+	pairs := map[string]model.User{}
+	pairMatches := make([]model.Match, 0, len(matches))
+	for _, match := range matches {
+		if !match.IsDoubles() || match.Status != model.MatchConfirmed {
+			continue
+		}
+		keyA, keyB := pairKey(match.SideA()), pairKey(match.SideB())
+		for key, side := range map[string][]string{keyA: match.SideA(), keyB: match.SideB()} {
+			if _, ok := pairs[key]; !ok {
+				pairs[key] = s.sideUser(side)
+			}
+		}
+		conceded := ""
+		switch match.ConcededBy {
+		case match.PlayerAID:
+			conceded = keyA
+		case match.PlayerBID:
+			conceded = keyB
+		}
+		match.PlayerAID, match.PlayerBID = keyA, keyB
+		match.PartnerAID, match.PartnerBID = "", ""
+		match.ConcededBy = conceded
+		pairMatches = append(pairMatches, match)
+	}
+	players := make([]model.User, 0, len(pairs))
+	for _, pair := range pairs {
+		players = append(players, pair)
+	}
+	return BuildStandings(players, pairMatches, opts)
+}
+
+// canConfirmResult lets any player from the side opposing the reporter
+// confirm or reject a result. When the reporter did not play, for example a
+// league admin, every player of the match may answer.
+func canConfirmResult(sideA, sideB []string, reportedBy, userID string) bool {
+	onA, onB := slices.Contains(sideA, userID), slices.Contains(sideB, userID)
+	if userID == "" || userID == reportedBy || (!onA && !onB) {
+		return false
+	}
+	switch {
+	case slices.Contains(sideA, reportedBy):
+		return onB
+	case slices.Contains(sideB, reportedBy):
+		return onA
+	}
+	return true
+}
+
+func distinctPlayers(ids ...string) bool {
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
@@ -10,6 +10,106 @@ func flashMessage(notice string) string {
 		return "Dziękujemy! Zgłoszenie zostało zapisane."
 	case "join_requested":
 		return "Wysłano prośbę o dołączenie do ligi."
+	case "season_started":
+		return "Nowy sezon został utworzony, a tabela poprzedniego sezonu zamrożona."
+	case "division_created":
+		return "Dodano dywizję."
+	case "division_removed":
+		return "Usunięto dywizję, jej gracze czekają na przydział."
+	case "division_assigned":
+		return "Zmieniono przydział gracza do dywizji."
+	case "division_rules_saved":
+		return "Zapisano zasady awansów i spadków."
+	case "player_removed":
+		return "Gracz został usunięty z ligi."
+	case "player_withdrawn":
+		return "Wycofano cię z ligi. Twoje dotychczasowe mecze pozostają w historii."
+	case "player_suspended":
+		return "Gracz został zawieszony."
+	case "player_reinstated":
+		return "Zawieszenie gracza zostało zakończone."
+	case "handicap_saved":
+		return "Zapisano forę gracza. Nowa wartość obowiązuje w kolejnych meczach."
+	case "team_created":
+		return "Dodano drużynę."
+	case "team_player_added":
+		return "Dodano gracza do drużyny."
+	case "order_saved":
+		return "Zapisano kolejność gry."
+	case "rubber_reported":
+		return "Wynik meczu został zgłoszony i czeka na potwierdzenie."
+	case "match_corrected":
+		return "Wynik meczu został poprawiony, tabela ligi uwzględnia zmianę."
+	case "match_disputed":
+		return "Wynik został odrzucony, a twoja propozycja przekazana zgłaszającemu."
+	case "dispute_accepted":
+		return "Zaakceptowano propozycję wyniku, mecz jest potwierdzony."
+	case "dispute_escalated":
+		return "Spór został przekazany administratorom ligi."
+	case "dispute_resolved":
+		return "Spór został rozstrzygnięty."
+	case "visibility_saved":
+		return "Zapisano widoczność ligi."
+	case "join_code_saved":
+		return "Zaktualizowano kod dołączenia."
+	case "invite_created":
+		return "Utworzono link z zaproszeniem."
+	case "invite_revoked":
+		return "Zaproszenie zostało wycofane."
+	case "league_joined":
+		return "Dołączono do ligi."
+	case "join_waitlisted":
+		return "Liga jest pełna – trafiasz na listę rezerwową."
+	case "request_waitlisted":
+		return "Liga jest pełna, gracz trafił na listę rezerwową."
+	case "capacity_saved":
+		return "Zapisano limit graczy."
+	case "league_updated":
+		return "Zapisano ustawienia ligi."
+	case "league_archived":
+		return "Liga została usunięta. Jej gracze nadal widzą historię meczów."
+	case "league_restored":
+		return "Liga została przywrócona."
+	case "ownership_nominated":
+		return "Wysłano propozycję przejęcia ligi."
+	case "ownership_cancelled":
+		return "Wycofano propozycję przejęcia ligi."
+	case "ownership_accepted":
+		return "Liga ma nowego właściciela."
+	case "ownership_declined":
+		return "Odrzucono propozycję przejęcia ligi."
+	case "schedule_proposed":
+		return "Wysłano propozycję terminów."
+	case "schedule_accepted":
+		return "Termin meczu został ustalony."
+	case "schedule_cancelled":
+		return "Mecz został odwołany."
+	case "result_reported":
+		return "Wynik został zgłoszony i czeka na potwierdzenie."
+	case "calendar_created":
+		return "Link do kalendarza jest gotowy. Poprzedni link przestał działać."
+	case "club_created":
+		return "Dodano klub. Jesteś jego administratorem."
+	case "club_updated":
+		return "Zapisano dane klubu."
+	case "court_added":
+		return "Dodano kort."
+	case "court_removed":
+		return "Usunięto kort."
+	case "club_admin_added":
+		return "Dodano administratora klubu."
+	case "club_admin_removed":
+		return "Usunięto administratora klubu."
+	case "court_booked":
+		return "Kort został zarezerwowany."
+	case "booking_cancelled":
+		return "Rezerwacja kortu została anulowana."
+	case "permissions_saved":
+		return "Zapisano uprawnienia administratorów."
+	case "waitlist_left":
+		return "Opuszczono listę rezerwową."
+	case "waitlist_removed":
+		return "Usunięto gracza z listy rezerwowej."
 	}
 	return ""
 }
@@ -0,0 +1,114 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"sqoush-app/internal/model"
+)
+
+const maxHandicap = 8
+
+func (s *Server) handleHandicapUpdate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	if !league.Handicap {
+		http.Error(w, "liga nie jest rozgrywana z forami", http.StatusBadRequest)
+		return
+	}
+	userID := r.FormValue("user_id")
+	if !isLeaguePlayer(league, userID) {
+		http.Error(w, "gracz nie należy do ligi", http.StatusBadRequest)
+		return
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(r.FormValue("handicap")))
+	if err != nil || value < 0 || value > maxHandicap {
+		http.Error(w, fmt.Sprintf("fora musi mieścić się w zakresie 0-%d", maxHandicap), http.StatusBadRequest)
+		return
+	}
+	if league.Handicaps == nil {
+		league.Handicaps = map[string]int{}
+	}
+	league.Handicaps[userID] = value
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=handicap_saved#handicaps", http.StatusSeeOther)
+}
+
+// defaultHandicap maps the self-declared skill level to a starting handicap
+// until an admin sets one for the league.
+func defaultHandicap(skill model.SkillLevel) int {
+	switch skill {
+	case model.SkillPro:
+		return 0
+	case model.SkillIntermediate:
+		return 2
+	}
+	return 4
+}
+
+func playerHandicap(league model.League, user model.User) int {
+	if value, ok := league.Handicaps[user.ID]; ok {
+		return value
+	}
+	return defaultHandicap(user.Skill)
+}
+
+// headStarts returns the points each side starts a set with. The side with
+// the higher handicap gets the difference, so the stronger side starts
+// behind. A pair plays off the mean of its partners' handicaps.
+func (s *Server) headStarts(league model.League, sideA, sideB []string) (int, int) {
+	if !league.Handicap {
+		return 0, 0
+	}
+	diff := s.sideHandicap(league, sideA) - s.sideHandicap(league, sideB)
+	limit := setFormatForLeague(league).PointsToWin - 2
+	switch {
+	case diff > 0:
+		return min(diff, limit), 0
+	case diff < 0:
+		return 0, min(-diff, limit)
+	}
+	return 0, 0
+}
+
+func (s *Server) sideHandicap(league model.League, ids []string) int {
+	players := s.usersByID(ids)
+	if len(players) == 0 {
+		return 0
+	}
+	total := 0
+	for _, player := range players {
+		total += playerHandicap(league, player)
+	}
+	return total / len(players)
+}
+
+func (s *Server) handicapViews(league model.League) []HandicapView {
+	views := []HandicapView{}
+	for _, player := range s.leaguePlayers(league) {
+		_, custom := league.Handicaps[player.ID]
+		views = append(views, HandicapView{
+			Player: player,
+			Value:  playerHandicap(league, player),
+			Custom: custom,
+		})
+	}
+	return views
+}
+
+func headStartLabel(match model.Match, playerA, playerB model.User) string {
+	switch {
+	case match.HeadStartA > 0:
+		return fmt.Sprintf("Fora: %s zaczyna każdy set z %d pkt", playerA.FullName(), match.HeadStartA)
+	case match.HeadStartB > 0:
+		return fmt.Sprintf("Fora: %s zaczyna każdy set z %d pkt", playerB.FullName(), match.HeadStartB)
+	}
+	return ""
+}
@@ -0,0 +1,226 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/booking"
+	"sqoush-app/internal/model"
+)
+
+const bookingDayLayout = "2006-01-02"
+
+// bookingSlotWindow is how far from a match's agreed time a slot may start
+// to be offered for that match.
+const bookingSlotWindow = booking.SlotLength
+
+func (s *Server) handleCourtBook(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.store.GetClub(chi.URLParam(r, "clubID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if currentUser.ID == "" {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	start, ok := parseBookingStart(w, r)
+	if !ok {
+		return
+	}
+	if _, err := s.bookings.Book(club, booking.Request{CourtID: r.FormValue("court_id"), UserID: currentUser.ID, Start: start}); err != nil {
+		bookingError(w, err)
+		return
+	}
+	http.Redirect(w, r, "/clubs/"+club.ID+"?day="+start.Format(bookingDayLayout)+"&notice=court_booked#bookings", http.StatusSeeOther)
+}
+
+// handleMatchCourtBook books a court for a match with an agreed date at a
+// club. Either player may do it; the other sees the booking on the match.
+func (s *Server) handleMatchCourtBook(w http.ResponseWriter, r *http.Request) {
+	match, _, ok := s.schedulableMatch(w, r)
+	if !ok {
+		return
+	}
+	club, ok := s.store.GetClub(match.ClubID)
+	if !ok || match.ScheduledAt == nil {
+		http.Error(w, "kort można zarezerwować dla meczu z ustalonym terminem w klubie", http.StatusBadRequest)
+		return
+	}
+	if _, booked := s.bookings.ForMatch(club, match.ID); booked {
+		http.Error(w, "ten mecz ma już zarezerwowany kort", http.StatusBadRequest)
+		return
+	}
+	start, ok := parseBookingStart(w, r)
+	if !ok {
+		return
+	}
+	if !withinBookingWindow(*match.ScheduledAt, start) {
+		http.Error(w, "wybierz termin bliski godzinie meczu", http.StatusBadRequest)
+		return
+	}
+	request := booking.Request{CourtID: r.FormValue("court_id"), UserID: s.currentUser(r).ID, MatchID: match.ID, Start: start}
+	if _, err := s.bookings.Book(club, request); err != nil {
+		bookingError(w, err)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=court_booked#schedule", http.StatusSeeOther)
+}
+
+func (s *Server) handleCourtBookingCancel(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.store.GetClub(chi.URLParam(r, "clubID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	existing, ok := s.bookings.Get(club, chi.URLParam(r, "bookingID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !s.canCancelBooking(club, existing, s.currentUser(r)) {
+		http.Error(w, "rezerwację może anulować jej autor, gracz meczu lub administrator klubu", http.StatusForbidden)
+		return
+	}
+	if err := s.bookings.Cancel(club, existing.ID); err != nil {
+		bookingError(w, err)
+		return
+	}
+	redirectBack(w, r, "/clubs/"+club.ID+"?day="+existing.Start.Format(bookingDayLayout), "booking_cancelled")
+}
+
+// releaseMatchBooking frees the court held for a match whose date no longer
+// stands.
+func (s *Server) releaseMatchBooking(match model.Match) error {
+	club, ok := s.store.GetClub(match.ClubID)
+	if !ok {
+		return nil
+	}
+	existing, ok := s.bookings.ForMatch(club, match.ID)
+	if !ok {
+		return nil
+	}
+	return s.bookings.Cancel(club, existing.ID)
+}
+
+func (s *Server) canCancelBooking(club model.Club, existing model.CourtBooking, user model.User) bool {
+	if user.ID == "" || !existing.Active() {
+		return false
+	}
+	if existing.UserID == user.ID || canManageClub(club, user) {
+		return true
+	}
+	match, ok := s.store.GetMatch(existing.MatchID)
+	return ok && match.Involves(user.ID)
+}
+
+func parseBookingStart(w http.ResponseWriter, r *http.Request) (time.Time, bool) {
+	start, err := time.ParseInLocation("2006-01-02T15:04", strings.TrimSpace(r.FormValue("start")), time.Local)
+	if err != nil {
+		http.Error(w, "nieprawidłowy termin rezerwacji", http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	if !start.After(time.Now()) {
+		http.Error(w, "nie można rezerwować terminów z przeszłości", http.StatusBadRequest)
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+func bookingError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, booking.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, booking.ErrUnknownCourt), errors.Is(err, booking.ErrNoSlot), errors.Is(err, booking.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func withinBookingWindow(scheduledAt, start time.Time) bool {
+	diff := start.Sub(scheduledAt)
+	return diff > -bookingSlotWindow && diff < bookingSlotWindow
+}
+
+// bookingDayView lays the day's slots out as a table with a row per start
+// time and a column per court.
+func (s *Server) bookingDayView(club model.Club, day time.Time, currentUser model.User, now time.Time) BookingDayView {
+	view := BookingDayView{
+		Day:      day.Format(bookingDayLayout),
+		DayLabel: weekdayLabels[day.Weekday()] + ", " + day.Format("02 Jan 2006"),
+		PrevDay:  day.AddDate(0, 0, -1).Format(bookingDayLayout),
+		NextDay:  day.AddDate(0, 0, 1).Format(bookingDayLayout),
+		Courts:   club.Courts,
+	}
+	slots, err := s.bookings.Day(club, day)
+	if err != nil {
+		view.Error = err.Error()
+		return view
+	}
+	rows := map[time.Time]int{}
+	for _, slot := range slots {
+		index, ok := rows[slot.Start]
+		if !ok {
+			index = len(view.Rows)
+			rows[slot.Start] = index
+			view.Rows = append(view.Rows, BookingRowView{Label: slot.Start.Format("15:04") + "–" + slot.End.Format("15:04")})
+		}
+		cell := BookingCellView{
+			CourtID: slot.CourtID,
+			Start:   slot.Start.Format("2006-01-02T15:04"),
+			Free:    slot.Booking == nil,
+			Past:    !slot.Start.After(now),
+		}
+		if slot.Booking != nil {
+			cell.BookingID = slot.Booking.ID
+			cell.MatchID = slot.Booking.MatchID
+			cell.BookedBy, _ = s.store.GetUser(slot.Booking.UserID)
+			cell.CanCancel = !cell.Past && s.canCancelBooking(club, *slot.Booking, currentUser)
+		}
+		view.Rows[index].Cells = append(view.Rows[index].Cells, cell)
+	}
+	return view
+}
+
+// matchBookingView shows the court held for a scheduled match, or the free
+// slots around its agreed time when there is none yet.
+func (s *Server) matchBookingView(match model.Match, currentUser model.User, now time.Time) *MatchBookingView {
+	club, ok := s.store.GetClub(match.ClubID)
+	if !ok || match.ScheduledAt == nil || len(club.Courts) == 0 {
+		return nil
+	}
+	view := &MatchBookingView{Club: club}
+	if existing, ok := s.bookings.ForMatch(club, match.ID); ok {
+		court, _ := club.Court(existing.CourtID)
+		view.BookingID = existing.ID
+		view.Label = court.Name + ", " + existing.Start.Format("15:04") + "–" + existing.End.Format("15:04")
+		view.CanCancel = s.canCancelBooking(club, existing, currentUser)
+		return view
+	}
+	slots, err := s.bookings.Day(club, *match.ScheduledAt)
+	if err != nil {
+		return view
+	}
+	for _, slot := range slots {
+		if slot.Booking != nil || !slot.Start.After(now) || !withinBookingWindow(*match.ScheduledAt, slot.Start) {
+			continue
+		}
+		court, _ := club.Court(slot.CourtID)
+		view.Slots = append(view.Slots, BookingSlotView{
+			CourtID: slot.CourtID,
+			Start:   slot.Start.Format("2006-01-02T15:04"),
+			Label:   court.Name + ", " + slot.Start.Format("15:04") + "–" + slot.End.Format("15:04"),
+		})
+	}
+	return view
+}
@@ -0,0 +1,257 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/ical"
+	"sqoush-app/internal/model"
+)
+
+const (
+	matchEventLength   = time.Hour
+	fixtureEventLength = 3 * time.Hour
+)
+
+// handleCalendarFeedCreate gives the user a secret feed URL, or a new one
+// when the old link leaked. The previous URL stops working.
+func (s *Server) handleCalendarFeedCreate(w http.ResponseWriter, r *http.Request) {
+	currentUser := s.currentUser(r)
+	if currentUser.ID == "" {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if _, err := s.saveCalendarFeed(currentUser.ID, ""); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/?notice=calendar_created#calendar", http.StatusSeeOther)
+}
+
+func (s *Server) handleLeagueCalendarFeedCreate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	currentUser := s.currentUser(r)
+	if !ok || !canViewLeague(league, currentUser) {
+		http.NotFound(w, r)
+		return
+	}
+	if currentUser.ID == "" {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if _, err := s.saveCalendarFeed(currentUser.ID, league.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=calendar_created#calendar", http.StatusSeeOther)
+}
+
+func (s *Server) saveCalendarFeed(userID, leagueID string) (model.CalendarFeed, error) {
+	token, err := randomToken()
+	if err != nil {
+		return model.CalendarFeed{}, err
+	}
+	return s.store.SaveCalendarFeed(model.CalendarFeed{UserID: userID, LeagueID: leagueID, Token: token})
+}
+
+// calendarURL is the subscription link of the user's feed, empty until the
+// user asks for one.
+func (s *Server) calendarURL(r *http.Request, userID, leagueID string) string {
+	feed, ok := s.store.GetCalendarFeed(userID, leagueID)
+	if !ok {
+		return ""
+	}
+	return absoluteURL(r, calendarFeedPath(feed))
+}
+
+func calendarFeedPath(feed model.CalendarFeed) string {
+	if feed.LeagueID != "" {
+		return "/calendar/league/" + feed.Token + ".ics"
+	}
+	return "/calendar/user/" + feed.Token + ".ics"
+}
+
+// handleUserCalendar serves the feed of everything the token's owner plays:
+// scheduled league matches, team fixtures, friendlies and league dates.
+func (s *Server) handleUserCalendar(w http.ResponseWriter, r *http.Request) {
+	feed, ok := s.calendarFeed(r)
+	if !ok || feed.LeagueID != "" {
+		http.NotFound(w, r)
+		return
+	}
+	user, ok := s.store.GetUser(feed.UserID)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	calendar := ical.Calendar{Name: "Squash – " + user.FullName()}
+	for _, league := range s.leaguesForUser(user.ID) {
+		if league.IsArchived() {
+			continue
+		}
+		calendar.Events = append(calendar.Events, leagueDateEvents(r, league)...)
+		for _, match := range s.store.ListMatches(league.ID) {
+			if match.Involves(user.ID) {
+				if event, ok := s.matchEvent(r, league, match); ok {
+					calendar.Events = append(calendar.Events, event)
+				}
+			}
+		}
+		for _, fixture := range s.store.ListFixtures(league.ID) {
+			if fixtureInvolves(league, fixture, user.ID) {
+				calendar.Events = append(calendar.Events, s.fixtureEvent(r, league, fixture))
+			}
+		}
+	}
+	for _, match := range s.store.ListFriendlyMatches() {
+		if match.Status == model.MatchRejected || !friendlyInvolves(match, user.ID) {
+			continue
+		}
+		calendar.Events = append(calendar.Events, s.friendlyEvent(match))
+	}
+	s.writeCalendar(w, calendar)
+}
+
+// handleLeagueCalendar serves one league's dates, scheduled matches and
+// fixtures. The feed dies with its owner's access to the league.
+func (s *Server) handleLeagueCalendar(w http.ResponseWriter, r *http.Request) {
+	feed, ok := s.calendarFeed(r)
+	if !ok || feed.LeagueID == "" {
+		http.NotFound(w, r)
+		return
+	}
+	league, ok := s.store.GetLeague(feed.LeagueID)
+	owner, userOK := s.store.GetUser(feed.UserID)
+	if !ok || !userOK || !canViewLeague(league, owner) {
+		http.NotFound(w, r)
+		return
+	}
+	calendar := ical.Calendar{Name: league.Name, Events: leagueDateEvents(r, league)}
+	for _, match := range s.store.ListMatches(league.ID) {
+		if event, ok := s.matchEvent(r, league, match); ok {
+			calendar.Events = append(calendar.Events, event)
+		}
+	}
+	for _, fixture := range s.store.ListFixtures(league.ID) {
+		calendar.Events = append(calendar.Events, s.fixtureEvent(r, league, fixture))
+	}
+	s.writeCalendar(w, calendar)
+}
+
+func (s *Server) calendarFeed(r *http.Request) (model.CalendarFeed, bool) {
+	token, ok := strings.CutSuffix(chi.URLParam(r, "feed"), ".ics")
+	if !ok || token == "" {
+		return model.CalendarFeed{}, false
+	}
+	return s.store.GetCalendarFeedByToken(token)
+}
+
+func (s *Server) writeCalendar(w http.ResponseWriter, calendar ical.Calendar) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := calendar.Write(w, time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func leagueDateEvents(r *http.Request, league model.League) []ical.Event {
+	url := absoluteURL(r, "/leagues/"+league.ID)
+	events := []ical.Event{{
+		UID:     "league-" + league.ID + "-start@sqoush",
+		Start:   league.StartDate,
+		AllDay:  true,
+		Summary: "Start ligi: " + league.Name,
+		URL:     url,
+	}}
+	if league.EndDate != nil {
+		events = append(events, ical.Event{
+			UID:     "league-" + league.ID + "-end@sqoush",
+			Start:   *league.EndDate,
+			AllDay:  true,
+			Summary: "Koniec ligi: " + league.Name,
+			URL:     url,
+		})
+	}
+	return events
+}
+
+// matchEvent describes a league match with an agreed date. Matches that are
+// only being arranged have no time yet and stay out of the feed.
+func (s *Server) matchEvent(r *http.Request, league model.League, match model.Match) (ical.Event, bool) {
+	if match.ScheduledAt == nil || match.FixtureID != "" {
+		return ical.Event{}, false
+	}
+	view := s.matchView(match, model.User{})
+	event := ical.Event{
+		UID:      "match-" + match.ID + "@sqoush",
+		Start:    *match.ScheduledAt,
+		End:      match.ScheduledAt.Add(matchEventLength),
+		Summary:  fmt.Sprintf("%s: %s vs %s", league.Name, view.PlayerA.FullName(), view.PlayerB.FullName()),
+		Location: match.Venue,
+		Status:   ical.StatusConfirmed,
+		URL:      absoluteURL(r, "/matches/"+match.ID),
+	}
+	if club, ok := s.store.GetClub(match.ClubID); ok && club.Address != "" {
+		event.Location += ", " + club.Address
+	}
+	switch match.Status {
+	case model.MatchVoided:
+		event.Status = ical.StatusCancelled
+	case model.MatchScheduled:
+	default:
+		event.Description = "Wynik: " + view.ScoreLine
+	}
+	return event, true
+}
+
+func (s *Server) fixtureEvent(r *http.Request, league model.League, fixture model.Fixture) ical.Event {
+	home, _ := teamByID(league, fixture.HomeTeamID)
+	away, _ := teamByID(league, fixture.AwayTeamID)
+	return ical.Event{
+		UID:      "fixture-" + fixture.ID + "@sqoush",
+		Start:    fixture.PlayAt,
+		End:      fixture.PlayAt.Add(fixtureEventLength),
+		Summary:  fmt.Sprintf("%s: %s – %s", league.Name, home.Name, away.Name),
+		Location: home.Club,
+		Status:   ical.StatusConfirmed,
+		URL:      absoluteURL(r, "/fixtures/"+fixture.ID),
+	}
+}
+
+func (s *Server) friendlyEvent(match model.FriendlyMatch) ical.Event {
+	playerA := s.sideUser(match.SideA())
+	playerB := s.sideUser(match.SideB())
+	conceded := playerA
+	if match.ConcededBy == match.PlayerBID {
+		conceded = playerB
+	}
+	event := ical.Event{
+		UID:     "friendly-" + match.ID + "@sqoush",
+		Start:   match.PlayedAt,
+		End:     match.PlayedAt.Add(matchEventLength),
+		Summary: fmt.Sprintf("Mecz towarzyski: %s vs %s", playerA.FullName(), playerB.FullName()),
+		Status:  ical.StatusConfirmed,
+	}
+	if score := formatScoreLine(match.Sets, match.Outcome, conceded); score != "" {
+		event.Description = "Wynik: " + score
+	}
+	return event
+}
+
+func fixtureInvolves(league model.League, fixture model.Fixture, userID string) bool {
+	for _, teamID := range []string{fixture.HomeTeamID, fixture.AwayTeamID} {
+		if team, ok := teamByID(league, teamID); ok && (team.CaptainID == userID || slices.Contains(team.PlayerIDs, userID)) {
+			return true
+		}
+	}
+	return false
+}
+
+func friendlyInvolves(match model.FriendlyMatch, userID string) bool {
+	return userID != "" && slices.Contains(match.Players(), userID)
+}
@@ -0,0 +1,406 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"sqoush-app/internal/model"
+)
+
+const clubRecentResults = 10
+
+var weekdayLabels = map[time.Weekday]string{
+	time.Monday:    "Poniedziałek",
+	time.Tuesday:   "Wtorek",
+	time.Wednesday: "Środa",
+	time.Thursday:  "Czwartek",
+	time.Friday:    "Piątek",
+	time.Saturday:  "Sobota",
+	time.Sunday:    "Niedziela",
+}
+
+// clubWeekdays is the week in the order it is shown, starting on Monday.
+var clubWeekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+func canManageClub(club model.Club, user model.User) bool {
+	return isSuperAdmin(user) || club.IsAdmin(user.ID)
+}
+
+func (s *Server) handleClubs(w http.ResponseWriter, r *http.Request) {
+	s.renderClubs(w, r, ClubForm{}, nil)
+}
+
+// handleClubCreate registers a club. Whoever adds it becomes its first
+// admin.
+func (s *Server) handleClubCreate(w http.ResponseWriter, r *http.Request) {
+	currentUser := s.currentUser(r)
+	if currentUser.ID == "" {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	form := clubFormFromRequest(r)
+	club, errors := applyClubForm(model.Club{}, form)
+	if len(errors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		s.renderClubs(w, r, form, errors)
+		return
+	}
+	club.ID = uuid.NewString()
+	club.AdminIDs = []string{currentUser.ID}
+	club.CreatedBy = currentUser.ID
+	club.CreatedAt = time.Now()
+	if _, err := s.store.CreateClub(club); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/clubs/"+club.ID+"?notice=club_created", http.StatusSeeOther)
+}
+
+func (s *Server) renderClubs(w http.ResponseWriter, r *http.Request, form ClubForm, errors []string) {
+	currentUser := s.currentUser(r)
+	leagueCounts := map[string]int{}
+	for _, league := range s.store.ListLeagues() {
+		if league.ClubID != "" && canListLeague(league, currentUser) {
+			leagueCounts[league.ClubID]++
+		}
+	}
+	items := []ClubListItem{}
+	for _, club := range s.store.ListClubs() {
+		items = append(items, ClubListItem{Club: club, Leagues: leagueCounts[club.ID]})
+	}
+	view := ClubsView{
+		BaseView: BaseView{
+			Title:           "Kluby",
+			CurrentUser:     currentUser,
+			Users:           s.store.ListUsers(),
+			IsAuthenticated: currentUser.ID != "",
+			IsDev:           isDevMode(),
+			FlashSuccess:    flashMessage(r.URL.Query().Get("notice")),
+		},
+		Clubs:  items,
+		Form:   form,
+		Errors: errors,
+	}
+	if err := s.templates.Render(w, "clubs.html", view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleClubShow(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.store.GetClub(chi.URLParam(r, "clubID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.renderClub(w, r, club, clubFormFromClub(club), nil)
+}
+
+func (s *Server) renderClub(w http.ResponseWriter, r *http.Request, club model.Club, form ClubForm, errors []string) {
+	currentUser := s.currentUser(r)
+	view := ClubView{
+		BaseView: BaseView{
+			Title:           club.Name,
+			CurrentUser:     currentUser,
+			Users:           s.store.ListUsers(),
+			IsAuthenticated: currentUser.ID != "",
+			IsDev:           isDevMode(),
+			FlashSuccess:    flashMessage(r.URL.Query().Get("notice")),
+		},
+		Club:      club,
+		Form:      form,
+		Errors:    errors,
+		Hours:     clubHoursView(club),
+		CanManage: canManageClub(club, currentUser),
+	}
+	if club.HasCoordinates() {
+		view.MapURL = fmt.Sprintf("https://www.openstreetmap.org/?mlat=%[1]f&mlon=%[2]f#map=17/%[1]f/%[2]f", club.Latitude, club.Longitude)
+	}
+	for _, id := range club.AdminIDs {
+		if user, ok := s.store.GetUser(id); ok {
+			view.Admins = append(view.Admins, user)
+		}
+	}
+	if view.CanManage {
+		for _, user := range s.store.ListUsers() {
+			if !club.IsAdmin(user.ID) {
+				view.AdminCandidates = append(view.AdminCandidates, user)
+			}
+		}
+	}
+	view.Leagues, view.Results = s.clubActivity(club, currentUser)
+	now := time.Now()
+	day, err := time.ParseInLocation(bookingDayLayout, r.URL.Query().Get("day"), time.Local)
+	if err != nil {
+		day = startOfDay(now)
+	}
+	view.Bookings = s.bookingDayView(club, day, currentUser, now)
+	if err := s.templates.Render(w, "club.html", view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// clubActivity lists the club's leagues and the latest confirmed results
+// played there: every match of a club league, plus matches of other leagues
+// scheduled at the club.
+func (s *Server) clubActivity(club model.Club, currentUser model.User) ([]model.League, []RecentActivityItem) {
+	leagues := []model.League{}
+	entries := []activityEntry{}
+	for _, league := range s.store.ListLeagues() {
+		if !canListLeague(league, currentUser) {
+			continue
+		}
+		if league.ClubID == club.ID {
+			leagues = append(leagues, league)
+		}
+		for _, match := range s.store.ListMatches(league.ID) {
+			if match.Status != model.MatchConfirmed || (league.ClubID != club.ID && match.ClubID != club.ID) {
+				continue
+			}
+			view := s.matchView(match, currentUser)
+			entries = append(entries, activityEntry{
+				When: match.ReportTime(),
+				Item: RecentActivityItem{
+					Kind:          "league",
+					MatchID:       match.ID,
+					PlayerA:       view.PlayerA,
+					PlayerB:       view.PlayerB,
+					ScoreLine:     view.ScoreLine,
+					StatusText:    view.StatusText,
+					PlayedAtLabel: match.ReportTime().Format("02 Jan 2006 15:04"),
+					LeagueName:    league.Name,
+				},
+			})
+		}
+	}
+	sort.Slice(leagues, func(i, j int) bool { return leagues[i].StartDate.After(leagues[j].StartDate) })
+	sort.Slice(entries, func(i, j int) bool { return entries[i].When.After(entries[j].When) })
+	results := []RecentActivityItem{}
+	for i := 0; i < len(entries) && i < clubRecentResults; i++ {
+		results = append(results, entries[i].Item)
+	}
+	return leagues, results
+}
+
+// managedClub loads the club from the URL for one of its admins, answering
+// the request itself when that fails.
+func (s *Server) managedClub(w http.ResponseWriter, r *http.Request) (model.Club, bool) {
+	club, ok := s.store.GetClub(chi.URLParam(r, "clubID"))
+	if !ok {
+		http.NotFound(w, r)
+		return model.Club{}, false
+	}
+	if !canManageClub(club, s.currentUser(r)) {
+		http.Error(w, "tylko administrator klubu może go edytować", http.StatusForbidden)
+		return model.Club{}, false
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return model.Club{}, false
+	}
+	return club, true
+}
+
+func (s *Server) saveClub(w http.ResponseWriter, r *http.Request, club model.Club, notice, anchor string) {
+	if err := s.store.UpdateClub(club); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/clubs/"+club.ID+"?notice="+notice+"#"+anchor, http.StatusSeeOther)
+}
+
+func (s *Server) handleClubUpdate(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.managedClub(w, r)
+	if !ok {
+		return
+	}
+	form := clubFormFromRequest(r)
+	updated, errors := applyClubForm(club, form)
+	if len(errors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		s.renderClub(w, r, club, form, errors)
+		return
+	}
+	s.saveClub(w, r, updated, "club_updated", "details")
+}
+
+// handleClubHours replaces the weekly opening hours. A day left blank is
+// closed.
+func (s *Server) handleClubHours(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.managedClub(w, r)
+	if !ok {
+		return
+	}
+	hours := []model.OpeningHours{}
+	for _, day := range clubWeekdays {
+		opens := strings.TrimSpace(r.FormValue(fmt.Sprintf("opens_%d", day)))
+		closes := strings.TrimSpace(r.FormValue(fmt.Sprintf("closes_%d", day)))
+		if opens == "" && closes == "" {
+			continue
+		}
+		openTime, openErr := time.Parse("15:04", opens)
+		closeTime, closeErr := time.Parse("15:04", closes)
+		if openErr != nil || closeErr != nil || !closeTime.After(openTime) {
+			http.Error(w, "nieprawidłowe godziny otwarcia: "+strings.ToLower(weekdayLabels[day]), http.StatusBadRequest)
+			return
+		}
+		hours = append(hours, model.OpeningHours{Weekday: day, Opens: opens, Closes: closes})
+	}
+	club.OpeningHours = hours
+	s.saveClub(w, r, club, "club_updated", "hours")
+}
+
+func (s *Server) handleClubCourtAdd(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.managedClub(w, r)
+	if !ok {
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		name = "Kort " + strconv.Itoa(len(club.Courts)+1)
+	}
+	for _, court := range club.Courts {
+		if strings.EqualFold(court.Name, name) {
+			http.Error(w, "kort o tej nazwie już istnieje", http.StatusBadRequest)
+			return
+		}
+	}
+	club.Courts = append(club.Courts, model.Court{ID: uuid.NewString(), Name: name})
+	s.saveClub(w, r, club, "court_added", "courts")
+}
+
+func (s *Server) handleClubCourtRemove(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.managedClub(w, r)
+	if !ok {
+		return
+	}
+	courtID := chi.URLParam(r, "courtID")
+	courts := slices.DeleteFunc(slices.Clone(club.Courts), func(court model.Court) bool { return court.ID == courtID })
+	if len(courts) == len(club.Courts) {
+		http.NotFound(w, r)
+		return
+	}
+	club.Courts = courts
+	s.saveClub(w, r, club, "court_removed", "courts")
+}
+
+func (s *Server) handleClubAdminAdd(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.managedClub(w, r)
+	if !ok {
+		return
+	}
+	user, ok := s.store.GetUser(r.FormValue("user_id"))
+	if !ok {
+		http.Error(w, "wybierz użytkownika", http.StatusBadRequest)
+		return
+	}
+	if !club.IsAdmin(user.ID) {
+		club.AdminIDs = append(club.AdminIDs, user.ID)
+	}
+	s.saveClub(w, r, club, "club_admin_added", "admins")
+}
+
+// handleClubAdminRemove drops an admin. The last one stays, so the club is
+// never left without someone to look after it.
+func (s *Server) handleClubAdminRemove(w http.ResponseWriter, r *http.Request) {
+	club, ok := s.managedClub(w, r)
+	if !ok {
+		return
+	}
+	userID := chi.URLParam(r, "userID")
+	if !club.IsAdmin(userID) {
+		http.NotFound(w, r)
+		return
+	}
+	if len(club.AdminIDs) == 1 {
+		http.Error(w, "klub musi mieć przynajmniej jednego administratora", http.StatusBadRequest)
+		return
+	}
+	club.AdminIDs = slices.DeleteFunc(slices.Clone(club.AdminIDs), func(id string) bool { return id == userID })
+	s.saveClub(w, r, club, "club_admin_removed", "admins")
+}
+
+func clubFormFromRequest(r *http.Request) ClubForm {
+	return ClubForm{
+		Name:      strings.TrimSpace(r.FormValue("name")),
+		Address:   strings.TrimSpace(r.FormValue("address")),
+		Latitude:  strings.TrimSpace(r.FormValue("latitude")),
+		Longitude: strings.TrimSpace(r.FormValue("longitude")),
+	}
+}
+
+func clubFormFromClub(club model.Club) ClubForm {
+	form := ClubForm{Name: club.Name, Address: club.Address}
+	if club.HasCoordinates() {
+		form.Latitude = strconv.FormatFloat(club.Latitude, 'f', -1, 64)
+		form.Longitude = strconv.FormatFloat(club.Longitude, 'f', -1, 64)
+	}
+	return form
+}
+
+// applyClubForm validates the club details. Coordinates are optional but
+// must be given together; a decimal comma is accepted.
+func applyClubForm(club model.Club, form ClubForm) (model.Club, []string) {
+	errors := []string{}
+	if form.Name == "" {
+		errors = append(errors, "Nazwa klubu jest wymagana.")
+	}
+	var latitude, longitude float64
+	if form.Latitude != "" || form.Longitude != "" {
+		var latErr, lngErr error
+		latitude, latErr = strconv.ParseFloat(strings.ReplaceAll(form.Latitude, ",", "."), 64)
+		longitude, lngErr = strconv.ParseFloat(strings.ReplaceAll(form.Longitude, ",", "."), 64)
+		if latErr != nil || lngErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+			errors = append(errors, "Podaj prawidłowe współrzędne: szerokość od -90 do 90 i długość od -180 do 180.")
+		}
+	}
+	if len(errors) > 0 {
+		return club, errors
+	}
+	club.Name = form.Name
+	club.Address = form.Address
+	club.Latitude = latitude
+	club.Longitude = longitude
+	return club, nil
+}
+
+func clubHoursView(club model.Club) []OpeningHoursView {
+	views := []OpeningHoursView{}
+	for _, day := range clubWeekdays {
+		view := OpeningHoursView{Weekday: int(day), Label: weekdayLabels[day], Closed: true}
+		for _, hours := range club.OpeningHours {
+			if hours.Weekday == day {
+				view.Opens, view.Closes, view.Closed = hours.Opens, hours.Closes, false
+			}
+		}
+		views = append(views, view)
+	}
+	return views
+}
+
+// clubLocation checks the club picked on a form. The free-text location
+// falls back to the club's name so older views keep showing something.
+func (s *Server) clubLocation(clubID, location string) (string, string, bool) {
+	if clubID == "" {
+		return "", location, true
+	}
+	club, ok := s.store.GetClub(clubID)
+	if !ok {
+		return "", location, false
+	}
+	if location == "" {
+		location = club.Name
+	}
+	return club.ID, location, true
+}
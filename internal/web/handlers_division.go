@@ -0,0 +1,358 @@
+package web
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"sqoush-app/internal/model"
+)
+
+const maxPromotionCount = 10
+
+func (s *Server) handleDivisionCreate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		http.Error(w, "podaj nazwę dywizji", http.StatusBadRequest)
+		return
+	}
+	divisions := sortedDivisions(league.Divisions)
+	league.Divisions = append(divisions, model.Division{
+		ID:    uuid.NewString(),
+		Name:  name,
+		Level: len(divisions) + 1,
+	})
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=division_created#divisions", http.StatusSeeOther)
+}
+
+func (s *Server) handleDivisionRemove(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	divisionID := chi.URLParam(r, "divisionID")
+	divisions := []model.Division{}
+	found := false
+	for _, division := range sortedDivisions(league.Divisions) {
+		if division.ID == divisionID {
+			found = true
+			continue
+		}
+		division.Level = len(divisions) + 1
+		divisions = append(divisions, division)
+	}
+	if !found {
+		http.NotFound(w, r)
+		return
+	}
+	league.Divisions = divisions
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=division_removed#divisions", http.StatusSeeOther)
+}
+
+func (s *Server) handleDivisionAssign(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	userID := r.FormValue("user_id")
+	if !isLeaguePlayer(league, userID) {
+		http.Error(w, "gracz nie należy do ligi", http.StatusBadRequest)
+		return
+	}
+	if !assignDivision(&league, userID, r.FormValue("division_id")) {
+		http.Error(w, "nie znaleziono dywizji", http.StatusBadRequest)
+		return
+	}
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=division_assigned#divisions", http.StatusSeeOther)
+}
+
+func (s *Server) handleDivisionPromotion(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(r.FormValue("promotion_count")))
+	if err != nil || count < 0 || count > maxPromotionCount {
+		http.Error(w, "liczba awansów musi mieścić się w zakresie 0-10", http.StatusBadRequest)
+		return
+	}
+	league.PromotionCount = count
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=division_rules_saved#divisions", http.StatusSeeOther)
+}
+
+func (s *Server) manageableLeague(w http.ResponseWriter, r *http.Request, capability model.Capability) (model.League, bool) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return model.League{}, false
+	}
+	if !can(league, s.currentUser(r), capability) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return model.League{}, false
+	}
+	if league.IsArchived() {
+		http.Error(w, "liga jest zarchiwizowana", http.StatusBadRequest)
+		return model.League{}, false
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return model.League{}, false
+	}
+	return league, true
+}
+
+// standingsGroups builds one table per division. A league without divisions
+// gets a single group holding all of its players.
+func (s *Server) standingsGroups(league model.League, matches []model.Match) []DivisionView {
+	opts := standingsOptionsForLeague(league)
+	if len(league.Divisions) == 0 {
+		players := s.tablePlayers(league, standingsPlayerIDs(league))
+		group := DivisionView{Players: players, Standings: markWithdrawn(league, BuildStandings(players, matches, opts))}
+		if league.Doubles {
+			group.PairStandings = s.pairStandings(matches, opts)
+		}
+		return []DivisionView{group}
+	}
+	groups := []DivisionView{}
+	for _, division := range sortedDivisions(league.Divisions) {
+		players := s.tablePlayers(league, division.PlayerIDs)
+		played := divisionMatches(matches, division)
+		group := DivisionView{
+			Division:  division,
+			Players:   players,
+			Standings: markWithdrawn(league, BuildStandings(players, played, opts)),
+		}
+		if league.Doubles {
+			group.PairStandings = s.pairStandings(played, opts)
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// standingsPlayerIDs lists current players followed by those who left.
+func standingsPlayerIDs(league model.League) []string {
+	ids := append([]string{}, league.PlayerIDs...)
+	for _, withdrawal := range league.Withdrawals {
+		if !slices.Contains(ids, withdrawal.UserID) {
+			ids = append(ids, withdrawal.UserID)
+		}
+	}
+	return ids
+}
+
+func (s *Server) usersByID(ids []string) []model.User {
+	users := make([]model.User, 0, len(ids))
+	for _, id := range ids {
+		if user, ok := s.store.GetUser(id); ok {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
+func (s *Server) unassignedPlayers(league model.League) []model.User {
+	unassigned := []model.User{}
+	for _, player := range s.leaguePlayers(league) {
+		if _, ok := league.DivisionOf(player.ID); !ok {
+			unassigned = append(unassigned, player)
+		}
+	}
+	return unassigned
+}
+
+// matchPlayers lists the players offered in the result form. Without admin
+// rights a player only sees opponents from their own division.
+func (s *Server) matchPlayers(league model.League, currentUser model.User, canManage bool) []model.User {
+	if len(league.Divisions) == 0 {
+		return s.activePlayers(league, league.PlayerIDs)
+	}
+	if !canManage {
+		division, ok := league.DivisionOf(currentUser.ID)
+		if !ok {
+			return []model.User{}
+		}
+		return s.activePlayers(league, division.PlayerIDs)
+	}
+	players := []model.User{}
+	for _, division := range sortedDivisions(league.Divisions) {
+		players = append(players, s.activePlayers(league, division.PlayerIDs)...)
+	}
+	return players
+}
+
+func frozenDivisionGroups(snapshot model.StandingsSnapshot) []DivisionView {
+	groups := []DivisionView{}
+	index := map[string]int{}
+	for _, entry := range snapshotStandingsEntries(snapshot) {
+		if entry.divisionID == "" {
+			continue
+		}
+		i, ok := index[entry.divisionID]
+		if !ok {
+			i = len(groups)
+			index[entry.divisionID] = i
+			groups = append(groups, DivisionView{Division: model.Division{ID: entry.divisionID, Name: entry.divisionName, Level: i + 1}})
+		}
+		groups[i].Standings = append(groups[i].Standings, entry.StandingEntry)
+	}
+	return groups
+}
+
+func divisionMatches(matches []model.Match, division model.Division) []model.Match {
+	members := make(map[string]bool, len(division.PlayerIDs))
+	for _, id := range division.PlayerIDs {
+		members[id] = true
+	}
+	filtered := []model.Match{}
+	for _, match := range matches {
+		if len(membersOnSide(members, match.Players())) == len(match.Players()) {
+			filtered = append(filtered, match)
+		}
+	}
+	return filtered
+}
+
+// sameDivision reports whether all given players share a division. Empty
+// IDs, such as the missing partners of a singles match, are skipped.
+func sameDivision(league model.League, playerIDs ...string) bool {
+	if len(league.Divisions) == 0 {
+		return true
+	}
+	divisionID := ""
+	for _, id := range playerIDs {
+		if id == "" {
+			continue
+		}
+		division, ok := league.DivisionOf(id)
+		if !ok || (divisionID != "" && division.ID != divisionID) {
+			return false
+		}
+		divisionID = division.ID
+	}
+	return true
+}
+
+// sortedDivisions returns copies of the divisions ordered from the top level.
+func sortedDivisions(divisions []model.Division) []model.Division {
+	sorted := make([]model.Division, 0, len(divisions))
+	for _, division := range divisions {
+		division.PlayerIDs = append([]string{}, division.PlayerIDs...)
+		sorted = append(sorted, division)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Level < sorted[j].Level })
+	return sorted
+}
+
+// assignDivision moves the player to the given division, or leaves them
+// unassigned when divisionID is empty.
+func assignDivision(league *model.League, userID, divisionID string) bool {
+	target := -1
+	for i, division := range league.Divisions {
+		if division.ID == divisionID {
+			target = i
+		}
+	}
+	if divisionID != "" && target < 0 {
+		return false
+	}
+	// The divisions share their backing array with the stored league.
+	league.Divisions = slices.Clone(league.Divisions)
+	for i, division := range league.Divisions {
+		kept := []string{}
+		for _, id := range division.PlayerIDs {
+			if id != userID {
+				kept = append(kept, id)
+			}
+		}
+		league.Divisions[i].PlayerIDs = kept
+	}
+	if target >= 0 {
+		league.Divisions[target].PlayerIDs = append(league.Divisions[target].PlayerIDs, userID)
+	}
+	return true
+}
+
+func keepDivisionPlayers(divisions []model.Division, playerIDs []string) []model.Division {
+	kept := make(map[string]bool, len(playerIDs))
+	for _, id := range playerIDs {
+		kept[id] = true
+	}
+	for i, division := range divisions {
+		ids := []string{}
+		for _, id := range division.PlayerIDs {
+			if kept[id] {
+				ids = append(ids, id)
+			}
+		}
+		divisions[i].PlayerIDs = ids
+	}
+	return divisions
+}
+
+// promoteRelegate applies the final table: the bottom count players of each
+// division swap places with the top count players of the division below. The
+// count is capped at half of each division so no player moves twice.
+func promoteRelegate(divisions []model.Division, snapshot model.StandingsSnapshot, count int) []model.Division {
+	ordered := sortedDivisions(divisions)
+	if count <= 0 || len(ordered) < 2 {
+		return ordered
+	}
+	ranked := map[string][]string{}
+	for _, entry := range snapshot.Entries {
+		ranked[entry.DivisionID] = append(ranked[entry.DivisionID], entry.PlayerID)
+	}
+	moves := map[string]int{}
+	for i := 0; i+1 < len(ordered); i++ {
+		upper := ranked[ordered[i].ID]
+		lower := ranked[ordered[i+1].ID]
+		n := min(count, len(upper)/2, len(lower)/2)
+		for _, id := range lower[:n] {
+			moves[id] = i
+		}
+		for _, id := range upper[len(upper)-n:] {
+			moves[id] = i + 1
+		}
+	}
+	next := make([]model.Division, len(ordered))
+	for i, division := range ordered {
+		division.Level = i + 1
+		division.PlayerIDs = []string{}
+		next[i] = division
+	}
+	for i, division := range ordered {
+		for _, id := range division.PlayerIDs {
+			target, moved := moves[id]
+			if !moved {
+				target = i
+			}
+			next[target].PlayerIDs = append(next[target].PlayerIDs, id)
+		}
+	}
+	return next
+}
@@ -1,9 +1,9 @@
 package web
 
 import (
-	"fmt"
 	"math"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -29,32 +29,7 @@ func (s *Server) handleFriendlyNew(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
-	view := FriendlyFormView{
-		LeagueUsers: s.store.ListUsers(),
-		Search: FriendlySearchView{
-			EmptyQuery: true,
-		},
-		SearchInput: FriendlySearchInputView{
-			Query: "",
-		},
-		PlayedAt: time.Now().Format("2006-01-02T15:04"),
-	}
-	page := struct {
-		BaseView
-		Form FriendlyFormView
-	}{
-		BaseView: BaseView{
-			Title:           "Nowy mecz towarzyski",
-			CurrentUser:     currentUser,
-			Users:           s.store.ListUsers(),
-			IsAuthenticated: true,
-			IsDev:           isDevMode(),
-		},
-		Form: view,
-	}
-	if err := s.templates.Render(w, "friendly_new.html", page); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-	}
+	s.renderFriendlyForm(w, currentUser, s.friendlyFormView("", SetFormat{}.normalized()))
 }
 
 func (s *Server) handleFriendlyDashboard(w http.ResponseWriter, r *http.Request) {
@@ -161,10 +136,26 @@ func (s *Server) handleFriendlyCreate(w http.ResponseWriter, r *http.Request) {
 		s.renderFriendlyFormError(w, currentUser, "Nie można wybrać siebie", r.FormValue("played_at"))
 		return
 	}
+	partnerID := r.FormValue("partner_id")
+	opponentPartnerID := r.FormValue("opponent_partner_id")
+	if partnerID != "" || opponentPartnerID != "" {
+		if !distinctPlayers(currentUser.ID, partnerID, opponent.ID, opponentPartnerID) {
+			s.renderFriendlyFormError(w, currentUser, "W deblu wybierz partnera i partnera przeciwnika – czterech różnych graczy", r.FormValue("played_at"))
+			return
+		}
+	}
+	format := parseSetFormat(r)
+	outcome, conceded := parseMatchOutcome(r)
 	setsCount := parseSetsCount(r.FormValue("sets_count"), 20)
-	sets := parseSets(r, setsCount)
-	if len(sets) == 0 {
-		s.renderFriendlyFormError(w, currentUser, "Uzupełnij przynajmniej jeden set (możesz zostawić puste pozostałe).", r.FormValue("played_at"))
+	sets, setErrs := parseSets(r, setsCount)
+	if len(setErrs) == 0 {
+		setErrs = validateMatchResult(sets, outcome, conceded, format)
+	}
+	if len(setErrs) > 0 {
+		form := s.friendlyFormView(r.FormValue("played_at"), format)
+		form.Error = "Popraw wynik meczu."
+		form.SetErrors = setErrorMessages(setErrs)
+		s.renderFriendlyForm(w, currentUser, form)
 		return
 	}
 	playedAt, err := parsePlayedAt(r.FormValue("played_at"))
@@ -177,7 +168,11 @@ func (s *Server) handleFriendlyCreate(w http.ResponseWriter, r *http.Request) {
 		ID:         uuid.NewString(),
 		PlayerAID:  currentUser.ID,
 		PlayerBID:  opponent.ID,
+		PartnerAID: partnerID,
+		PartnerBID: opponentPartnerID,
 		Sets:       sets,
+		Outcome:    outcome,
+		ConcededBy: sidePlayerID(conceded, currentUser.ID, opponent.ID),
 		Status:     model.MatchPending,
 		ReportedBy: currentUser.ID,
 		PlayedAt:   playedAt,
@@ -199,10 +194,16 @@ func (s *Server) handleFriendlyCreate(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) renderFriendlyFormError(w http.ResponseWriter, currentUser model.User, message string, playedAt string) {
+	form := s.friendlyFormView(playedAt, SetFormat{}.normalized())
+	form.Error = message
+	s.renderFriendlyForm(w, currentUser, form)
+}
+
+func (s *Server) friendlyFormView(playedAt string, format SetFormat) FriendlyFormView {
 	if playedAt == "" {
 		playedAt = time.Now().Format("2006-01-02T15:04")
 	}
-	view := FriendlyFormView{
+	return FriendlyFormView{
 		LeagueUsers: s.store.ListUsers(),
 		Search: FriendlySearchView{
 			EmptyQuery: true,
@@ -210,9 +211,13 @@ func (s *Server) renderFriendlyFormError(w http.ResponseWriter, currentUser mode
 		SearchInput: FriendlySearchInputView{
 			Query: "",
 		},
-		PlayedAt: playedAt,
-		Error:    message,
+		PlayedAt:     playedAt,
+		BestOf:       format.BestOf,
+		PointsPerSet: format.PointsToWin,
 	}
+}
+
+func (s *Server) renderFriendlyForm(w http.ResponseWriter, currentUser model.User, form FriendlyFormView) {
 	page := struct {
 		BaseView
 		Form FriendlyFormView
@@ -224,7 +229,7 @@ func (s *Server) renderFriendlyFormError(w http.ResponseWriter, currentUser mode
 			IsAuthenticated: true,
 			IsDev:           isDevMode(),
 		},
-		Form: view,
+		Form: form,
 	}
 	if err := s.templates.Render(w, "friendly_new.html", page); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -243,6 +248,10 @@ func (s *Server) handleFriendlyConfirm(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "nie możesz potwierdzić własnego wyniku", http.StatusForbidden)
 		return
 	}
+	if !canConfirmResult(match.SideA(), match.SideB(), match.ReportedBy, currentUser.ID) {
+		http.Error(w, "wynik potwierdza gracz drugiej strony", http.StatusForbidden)
+		return
+	}
 	match.Status = model.MatchConfirmed
 	match.ConfirmedBy = currentUser.ID
 	if err := s.store.UpdateFriendlyMatch(match); err != nil {
@@ -272,6 +281,10 @@ func (s *Server) handleFriendlyReject(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "nie możesz odrzucić własnego wyniku", http.StatusForbidden)
 		return
 	}
+	if !canConfirmResult(match.SideA(), match.SideB(), match.ReportedBy, currentUser.ID) {
+		http.Error(w, "wynik odrzuca gracz drugiej strony", http.StatusForbidden)
+		return
+	}
 	match.Status = model.MatchRejected
 	if err := s.store.UpdateFriendlyMatch(match); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -328,7 +341,7 @@ func (s *Server) friendlyDashboardView(currentUser model.User, params friendlyDa
 			continue
 		}
 		if params.OpponentID != "" {
-			if match.PlayerAID != params.OpponentID && match.PlayerBID != params.OpponentID {
+			if !match.Involves(params.OpponentID) {
 				continue
 			}
 		}
@@ -401,23 +414,21 @@ func (s *Server) friendlyDashboardView(currentUser model.User, params friendlyDa
 }
 
 func (s *Server) friendlyMatchView(match model.FriendlyMatch, currentUser model.User) FriendlyMatchView {
-	playerA, _ := s.store.GetUser(match.PlayerAID)
-	playerB, _ := s.store.GetUser(match.PlayerBID)
+	playerA := s.sideUser(match.SideA())
+	playerB := s.sideUser(match.SideB())
 
-	scoreParts := make([]string, 0, len(match.Sets))
-	for _, set := range match.Sets {
-		scoreParts = append(scoreParts, fmt.Sprintf("%d:%d", set.A, set.B))
+	conceded := playerA
+	if match.ConcededBy == match.PlayerBID {
+		conceded = playerB
 	}
-	scoreLine := strings.Join(scoreParts, ", ")
+	scoreLine := formatScoreLine(match.Sets, match.Outcome, conceded)
 
-	statusText := map[model.MatchStatus]string{
-		model.MatchScheduled: "Zaplanowany",
-		model.MatchPending:   "Oczekuje na potwierdzenie",
-		model.MatchConfirmed: "Potwierdzony",
-		model.MatchRejected:  "Odrzucony",
-	}[match.Status]
+	statusText := matchStatusText(match.Status)
+	if match.Status == model.MatchConfirmed && match.AutoConfirmed {
+		statusText = "Potwierdzony automatycznie"
+	}
 
-	canConfirm := match.Status == model.MatchPending && match.ReportedBy != currentUser.ID && (currentUser.ID == match.PlayerAID || currentUser.ID == match.PlayerBID)
+	canConfirm := match.Status == model.MatchPending && canConfirmResult(match.SideA(), match.SideB(), match.ReportedBy, currentUser.ID)
 	canReject := canConfirm
 
 	return FriendlyMatchView{
@@ -492,31 +503,23 @@ func buildFriendlySummary(currentUserID string, opponentName string, matches []m
 			continue
 		}
 		summary.Matches++
-		setsWon := 0
-		setsLost := 0
-		pointsWon := 0
-		pointsLost := 0
-		for _, set := range match.Sets {
-			a := set.A
-			b := set.B
-			if match.PlayerAID != currentUserID {
-				a, b = b, a
-			}
-			pointsWon += a
-			pointsLost += b
-			if a > b {
-				setsWon++
-			} else if b > a {
-				setsLost++
-			}
+		result := friendlyMatchResult(match)
+		mySide, otherSide := sideA, sideB
+		setsWon, setsLost := result.SetsA, result.SetsB
+		pointsWon, pointsLost := result.PointsA, result.PointsB
+		if !slices.Contains(match.SideA(), currentUserID) {
+			mySide, otherSide = sideB, sideA
+			setsWon, setsLost = setsLost, setsWon
+			pointsWon, pointsLost = pointsLost, pointsWon
 		}
 		summary.SetsWon += setsWon
 		summary.SetsLost += setsLost
 		summary.PointsWon += pointsWon
 		summary.PointsLost += pointsLost
-		if setsWon > setsLost {
+		switch result.Winner {
+		case mySide:
 			summary.Wins++
-		} else if setsLost > setsWon {
+		case otherSide:
 			summary.Losses++
 		}
 	}
@@ -526,6 +529,7 @@ func buildFriendlySummary(currentUserID string, opponentName string, matches []m
 func (s *Server) friendlySearchView(query string, excludeUserID string) FriendlySearchView {
 	results := []FriendlySearchResult{}
 	if query != "" {
+		ratings := s.playerRatings()
 		lowerQuery := strings.ToLower(query)
 		for _, user := range s.store.ListUsers() {
 			if user.ID == excludeUserID {
@@ -536,7 +540,7 @@ func (s *Server) friendlySearchView(query string, excludeUserID string) Friendly
 			if !strings.Contains(name, lowerQuery) && !strings.Contains(email, lowerQuery) {
 				continue
 			}
-			results = append(results, FriendlySearchResult{User: user})
+			results = append(results, FriendlySearchResult{User: user, Rating: ratings[user.ID]})
 		}
 	}
 	return FriendlySearchView{
@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"sqoush-app/internal/model"
 )
@@ -27,7 +28,8 @@ func (s *Server) handleHome(w http.ResponseWriter, r *http.Request) {
 	})
 
 	pendingEntries := s.leagueActivityEntries(currentUser, map[model.MatchStatus]bool{
-		model.MatchPending: true,
+		model.MatchPending:  true,
+		model.MatchDisputed: true,
 	})
 	sort.Slice(pendingEntries, func(i, j int) bool {
 		if pendingEntries[i].Item.CanConfirm != pendingEntries[j].Item.CanConfirm {
@@ -67,6 +69,9 @@ func (s *Server) handleHome(w http.ResponseWriter, r *http.Request) {
 		DashboardPending:    buildDashboardTabView(pendingEntries, 0, 6, "/matches/pending", "Brak meczów do potwierdzenia."),
 		DashboardFriendly:   buildDashboardTabView(friendlyEntries, 0, 6, "/friendlies/dashboard", "Brak wyników do wyświetlenia."),
 		LeagueSearch:        s.leagueSearchView("", currentUser, 1),
+		Upcoming:            s.upcomingMatches(currentUser, time.Now()),
+		CalendarURL:         s.calendarURL(r, currentUser.ID, ""),
+		Rating:              s.playerRatings()[currentUser.ID],
 	}
 	if err := s.templates.Render(w, "home.html", view); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -91,7 +96,8 @@ func (s *Server) handleDashboardRecentTab(w http.ResponseWriter, r *http.Request
 func (s *Server) handleDashboardPendingTab(w http.ResponseWriter, r *http.Request) {
 	currentUser := s.currentUser(r)
 	entries := s.leagueActivityEntries(currentUser, map[model.MatchStatus]bool{
-		model.MatchPending: true,
+		model.MatchPending:  true,
+		model.MatchDisputed: true,
 	})
 	sort.Slice(entries, func(i, j int) bool {
 		if entries[i].Item.CanConfirm != entries[j].Item.CanConfirm {
@@ -143,7 +149,7 @@ func (s *Server) dashboardJoinRequests(currentUser model.User) []JoinRequestDash
 	leagues := s.store.ListLeagues()
 	items := []JoinRequestDashboardItem{}
 	for _, league := range leagues {
-		if !canManageLeague(league, currentUser) {
+		if !can(league, currentUser, model.CapManagePlayers) {
 			continue
 		}
 		requests := s.store.ListJoinRequests(league.ID)
@@ -180,7 +186,7 @@ func (s *Server) availableLeaguesPage(currentUser model.User, r *http.Request) (
 	leagues := s.store.ListLeagues()
 	filtered := make([]model.League, 0, len(leagues))
 	for _, league := range leagues {
-		if isLeaguePlayer(league, currentUser.ID) || isLeagueAdmin(league, currentUser.ID) {
+		if isLeaguePlayer(league, currentUser.ID) || isLeagueAdmin(league, currentUser.ID) || !league.IsListed() || league.IsArchived() {
 			continue
 		}
 		filtered = append(filtered, league)
@@ -232,7 +238,8 @@ func (s *Server) availableLeaguesPage(currentUser model.User, r *http.Request) (
 func (s *Server) handleMatchesPending(w http.ResponseWriter, r *http.Request) {
 	currentUser := s.currentUser(r)
 	entries := s.leagueActivityEntries(currentUser, map[model.MatchStatus]bool{
-		model.MatchPending: true,
+		model.MatchPending:  true,
+		model.MatchDisputed: true,
 	})
 	sort.Slice(entries, func(i, j int) bool {
 		if entries[i].Item.CanConfirm != entries[j].Item.CanConfirm {
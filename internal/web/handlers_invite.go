@@ -0,0 +1,320 @@
+package web
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/waitlist"
+)
+
+const (
+	joinCodeLength   = 6
+	joinCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+var inviteValidityDays = []int{1, 7, 30}
+
+func parseVisibility(value string) model.LeagueVisibility {
+	switch visibility := model.LeagueVisibility(strings.TrimSpace(value)); visibility {
+	case model.VisibilityUnlisted, model.VisibilityPrivate:
+		return visibility
+	}
+	return model.VisibilityPublic
+}
+
+func visibilityLabel(visibility model.LeagueVisibility) string {
+	switch visibility {
+	case model.VisibilityUnlisted:
+		return "Niepubliczna – dostępna dla osób z linkiem"
+	case model.VisibilityPrivate:
+		return "Prywatna – tylko na zaproszenie"
+	}
+	return "Publiczna"
+}
+
+// canViewLeague hides private and archived leagues from everyone outside
+// them. Unlisted leagues stay reachable by their address.
+func canViewLeague(league model.League, user model.User) bool {
+	if league.Visibility != model.VisibilityPrivate && !league.IsArchived() {
+		return true
+	}
+	return canManageLeague(league, user) || isLeaguePlayer(league, user.ID)
+}
+
+// canListLeague decides whether the league shows up in search and on the
+// dashboard for the user.
+func canListLeague(league model.League, user model.User) bool {
+	if league.IsArchived() {
+		return false
+	}
+	return league.IsListed() || isSuperAdmin(user) || isLeaguePlayer(league, user.ID) || isLeagueAdmin(league, user.ID)
+}
+
+func (s *Server) handleLeagueVisibility(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	league.Visibility = parseVisibility(r.FormValue("visibility"))
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=visibility_saved#access", http.StatusSeeOther)
+}
+
+func (s *Server) handleJoinCodeUpdate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapManagePlayers)
+	if !ok {
+		return
+	}
+	league.JoinCode = ""
+	if r.FormValue("action") != "disable" {
+		code, err := s.newJoinCode()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		league.JoinCode = code
+	}
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=join_code_saved#access", http.StatusSeeOther)
+}
+
+// handleJoinByCode adds the current user to the league with the given code.
+// The code stands in for an admin's approval.
+func (s *Server) handleJoinByCode(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	code := strings.ToUpper(strings.TrimSpace(r.FormValue("code")))
+	league, ok := s.leagueByJoinCode(code)
+	if !ok {
+		http.Error(w, "nieprawidłowy kod dołączenia", http.StatusBadRequest)
+		return
+	}
+	joined, err := s.joinLeague(league, s.currentUser(r).ID, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice="+joinNotice(joined), http.StatusSeeOther)
+}
+
+func (s *Server) handleInviteCreate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapManagePlayers)
+	if !ok {
+		return
+	}
+	days, err := strconv.Atoi(r.FormValue("days"))
+	if err != nil || !containsInt(inviteValidityDays, days) {
+		http.Error(w, "nieprawidłowy czas ważności", http.StatusBadRequest)
+		return
+	}
+	token, err := randomToken()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	now := time.Now()
+	_, err = s.store.CreateLeagueInvite(model.LeagueInvite{
+		LeagueID:  league.ID,
+		Token:     token,
+		CreatedBy: s.currentUser(r).ID,
+		ExpiresAt: now.AddDate(0, 0, days),
+		CreatedAt: now,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=invite_created#access", http.StatusSeeOther)
+}
+
+func (s *Server) handleInviteRevoke(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapManagePlayers)
+	if !ok {
+		return
+	}
+	inviteID := chi.URLParam(r, "inviteID")
+	for _, invite := range s.store.ListLeagueInvites(league.ID) {
+		if invite.ID != inviteID {
+			continue
+		}
+		invite.Revoked = true
+		if err := s.store.UpdateLeagueInvite(invite); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/leagues/"+league.ID+"?notice=invite_revoked#access", http.StatusSeeOther)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+func (s *Server) handleInviteShow(w http.ResponseWriter, r *http.Request) {
+	currentUser := s.currentUser(r)
+	view := InvitePageView{
+		BaseView: BaseView{
+			Title:           "Zaproszenie do ligi",
+			CurrentUser:     currentUser,
+			Users:           s.store.ListUsers(),
+			IsAuthenticated: true,
+			IsDev:           isDevMode(),
+		},
+		Token: chi.URLParam(r, "token"),
+	}
+	invite, league, ok := s.activeInvite(view.Token)
+	if ok {
+		view.Valid = true
+		view.League = league
+		view.ExpiresLabel = invite.ExpiresAt.Format("02 Jan 2006 15:04")
+		view.AlreadyMember = isLeaguePlayer(league, currentUser.ID)
+	} else {
+		w.WriteHeader(http.StatusGone)
+	}
+	if err := s.templates.Render(w, "invite.html", view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleInviteAccept(w http.ResponseWriter, r *http.Request) {
+	invite, league, ok := s.activeInvite(chi.URLParam(r, "token"))
+	if !ok {
+		http.Error(w, "zaproszenie wygasło lub zostało wycofane", http.StatusGone)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if isLeaguePlayer(league, currentUser.ID) {
+		http.Redirect(w, r, "/leagues/"+league.ID, http.StatusSeeOther)
+		return
+	}
+	joined, err := s.joinLeague(league, currentUser.ID, invite.CreatedBy)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	invite.Uses++
+	if err := s.store.UpdateLeagueInvite(invite); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice="+joinNotice(joined), http.StatusSeeOther)
+}
+
+func (s *Server) activeInvite(token string) (model.LeagueInvite, model.League, bool) {
+	invite, ok := s.store.GetLeagueInviteByToken(token)
+	if !ok || !invite.Active(time.Now()) {
+		return model.LeagueInvite{}, model.League{}, false
+	}
+	league, ok := s.store.GetLeague(invite.LeagueID)
+	if !ok || league.IsArchived() {
+		return model.LeagueInvite{}, model.League{}, false
+	}
+	return invite, league, true
+}
+
+// joinLeague adds the player, or puts them on the waitlist when the league
+// is full. It reports whether the player joined.
+func (s *Server) joinLeague(league model.League, userID, approvedBy string) (bool, error) {
+	if isLeaguePlayer(league, userID) {
+		return true, nil
+	}
+	if league.OpenSlots() == 0 {
+		waitlist.Add(&league, userID, time.Now())
+		if err := s.store.UpdateLeague(league); err != nil {
+			return false, err
+		}
+		return false, s.decideJoinRequest(league, userID, approvedBy, model.JoinRequestWaitlisted)
+	}
+	if err := s.store.AddPlayerToLeague(league.ID, userID); err != nil {
+		return false, err
+	}
+	return true, s.decideJoinRequest(league, userID, approvedBy, model.JoinRequestApproved)
+}
+
+func (s *Server) leagueByJoinCode(code string) (model.League, bool) {
+	if code == "" {
+		return model.League{}, false
+	}
+	for _, league := range s.store.ListLeagues() {
+		if league.JoinCode == code && !league.IsArchived() {
+			return league, true
+		}
+	}
+	return model.League{}, false
+}
+
+func (s *Server) newJoinCode() (string, error) {
+	for {
+		buf := make([]byte, joinCodeLength)
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		code := make([]byte, joinCodeLength)
+		for i, b := range buf {
+			code[i] = joinCodeAlphabet[int(b)%len(joinCodeAlphabet)]
+		}
+		if _, taken := s.leagueByJoinCode(string(code)); !taken {
+			return string(code), nil
+		}
+	}
+}
+
+func (s *Server) inviteViews(r *http.Request, league model.League) []InviteView {
+	now := time.Now()
+	views := []InviteView{}
+	for _, invite := range s.store.ListLeagueInvites(league.ID) {
+		views = append(views, InviteView{
+			Invite:       invite,
+			URL:          absoluteURL(r, "/invites/"+invite.Token),
+			Active:       invite.Active(now),
+			ExpiresLabel: invite.ExpiresAt.Format("02 Jan 2006 15:04"),
+		})
+	}
+	return views
+}
+
+func joinNotice(joined bool) string {
+	if joined {
+		return "league_joined"
+	}
+	return "join_waitlisted"
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// absoluteURL builds a link to path on the host that served the request.
+func absoluteURL(r *http.Request, path string) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + path
+}
+
+func containsInt(values []int, needle int) bool {
+	for _, value := range values {
+		if value == needle {
+			return true
+		}
+	}
+	return false
+}
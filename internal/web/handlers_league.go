@@ -21,12 +21,17 @@ func (s *Server) handleLeagueNew(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
-	view := BaseView{
-		Title:           "Nowa liga",
-		CurrentUser:     currentUser,
-		Users:           s.store.ListUsers(),
-		IsAuthenticated: true,
-		IsDev:           isDevMode(),
+	view := LeagueFormView{
+		BaseView: BaseView{
+			Title:           "Nowa liga",
+			CurrentUser:     currentUser,
+			Users:           s.store.ListUsers(),
+			IsAuthenticated: true,
+			IsDev:           isDevMode(),
+		},
+		TieBreakPresets: tieBreakPresets,
+		TieBreakPreset:  tieBreakPresets[0].Value,
+		Clubs:           s.store.ListClubs(),
 	}
 	if err := s.templates.Render(w, "league_new.html", view); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -74,7 +79,15 @@ func (s *Server) handleLeagueJoin(w http.ResponseWriter, r *http.Request) {
 		http.Redirect(w, r, "/leagues/"+league.ID, http.StatusSeeOther)
 		return
 	}
-	if s.store.HasPendingJoinRequest(league.ID, currentUser.ID) {
+	if league.IsArchived() {
+		http.Error(w, "liga jest zarchiwizowana", http.StatusBadRequest)
+		return
+	}
+	if league.Visibility == model.VisibilityPrivate {
+		http.Error(w, "do tej ligi można dołączyć tylko przez zaproszenie lub kod", http.StatusForbidden)
+		return
+	}
+	if _, _, waiting := league.WaitlistEntry(currentUser.ID); waiting || s.store.HasPendingJoinRequest(league.ID, currentUser.ID) {
 		redirectBack(w, r, "/leagues/"+league.ID, "")
 		return
 	}
@@ -99,7 +112,13 @@ func (s *Server) handleLeagueCreate(w http.ResponseWriter, r *http.Request) {
 	name := strings.TrimSpace(r.FormValue("name"))
 	location := strings.TrimSpace(r.FormValue("location"))
 	description := strings.TrimSpace(r.FormValue("description"))
-	setsPerMatch := parseSetsPerMatch(r.FormValue("sets_per_match"))
+	setsPerMatch, setsOK := parseSetsPerMatch(r.FormValue("sets_per_match"))
+	pointsPerSet := parsePointsPerSet(r.FormValue("points_per_set"))
+	tieBreakers := parseTieBreakers(r.FormValue("tie_breakers"))
+	scoring := parseScoringRules(r)
+	confirmationHours := parseConfirmationHours(r.FormValue("confirmation_hours"))
+	autoConfirmAction := parseAutoConfirmAction(r.FormValue("auto_confirm_action"))
+	maxPlayers := parseMaxPlayers(r.FormValue("max_players"))
 	startDate, err := parseLeagueDate(r.FormValue("start_date"))
 	if err != nil {
 		http.Error(w, "nieprawidłowa data startu", http.StatusBadRequest)
@@ -114,30 +133,52 @@ func (s *Server) handleLeagueCreate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "nazwa jest wymagana", http.StatusBadRequest)
 		return
 	}
+	if !setsOK {
+		http.Error(w, "liczba setów na mecz musi być nieparzysta: 1, 3, 5, 7 lub 9", http.StatusBadRequest)
+		return
+	}
 	if endDate != nil && endDate.Before(startDate) {
 		http.Error(w, "data końca musi być po dacie startu", http.StatusBadRequest)
 		return
 	}
+	clubID, location, ok := s.clubLocation(r.FormValue("club_id"), location)
+	if !ok {
+		http.Error(w, "wybrany klub nie istnieje", http.StatusBadRequest)
+		return
+	}
 
 	currentUser := s.currentUser(r)
 	if currentUser.ID == "" {
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 		return
 	}
-	status := leagueStatusForDates(startDate, endDate, time.Now())
+	status := model.LeagueStatusForDates(startDate, endDate, time.Now())
 	league := model.League{
-		ID:           uuid.NewString(),
-		Name:         name,
-		Description:  description,
-		Location:     location,
-		OwnerID:      currentUser.ID,
-		AdminRoles:   map[string]model.LeagueAdminRole{currentUser.ID: model.LeagueAdminPlayer},
-		PlayerIDs:    []string{currentUser.ID},
-		SetsPerMatch: setsPerMatch,
-		StartDate:    startDate,
-		EndDate:      endDate,
-		Status:       status,
-		CreatedAt:    time.Now(),
+		ID:                uuid.NewString(),
+		Name:              name,
+		Description:       description,
+		Location:          location,
+		ClubID:            clubID,
+		OwnerID:           currentUser.ID,
+		AdminRoles:        map[string]model.LeagueAdminRole{currentUser.ID: model.LeagueAdminPlayer},
+		PlayerIDs:         []string{currentUser.ID},
+		SetsPerMatch:      setsPerMatch,
+		PointsPerSet:      pointsPerSet,
+		Scoring:           scoring,
+		TieBreakers:       tieBreakers,
+		ConfirmationHours: confirmationHours,
+		AutoConfirmAction: autoConfirmAction,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Status:            status,
+		Doubles:           r.FormValue("doubles") != "",
+		Competition:       parseCompetition(r.FormValue("competition")),
+		RubbersPerFixture: parseRubbersPerFixture(r.FormValue("rubbers_per_fixture")),
+		Handicap:          r.FormValue("handicap") != "",
+		WithdrawalPolicy:  parseWithdrawalPolicy(r.FormValue("withdrawal_policy"), model.WithdrawalKeep),
+		Visibility:        parseVisibility(r.FormValue("visibility")),
+		MaxPlayers:        maxPlayers,
+		CreatedAt:         time.Now(),
 	}
 	if _, err := s.store.CreateLeague(league); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -149,12 +190,13 @@ func (s *Server) handleLeagueCreate(w http.ResponseWriter, r *http.Request) {
 func (s *Server) handleLeagueShow(w http.ResponseWriter, r *http.Request) {
 	leagueID := chi.URLParam(r, "leagueID")
 	league, ok := s.store.GetLeague(leagueID)
-	if !ok {
+	currentUser := s.currentUser(r)
+	if !ok || !canViewLeague(league, currentUser) {
 		http.NotFound(w, r)
 		return
 	}
-	currentUser := s.currentUser(r)
-	canManage := canManageLeague(league, currentUser)
+	canManage := canManageLeague(league, currentUser) && !league.IsArchived()
+	permissions := leaguePermissions(league, currentUser)
 	players := s.leaguePlayers(league)
 	setsRange := buildSetsRange(league.SetsPerMatch)
 
@@ -186,6 +228,7 @@ func (s *Server) handleLeagueShow(w http.ResponseWriter, r *http.Request) {
 		matchViews = append(matchViews, s.matchView(match, s.currentUser(r)))
 	}
 
+	groups := s.standingsGroups(league, matches)
 	view := LeagueView{
 		BaseView: BaseView{
 			Title:           league.Name,
@@ -197,7 +240,11 @@ func (s *Server) handleLeagueShow(w http.ResponseWriter, r *http.Request) {
 		},
 		League:          league,
 		Players:         players,
-		Standings:       BuildStandings(players, matches),
+		MatchPlayers:    s.matchPlayers(league, currentUser, permissions.EditResults),
+		TieBreakers:     tieBreakLabels(league.TieBreakers),
+		SetFormat:       setFormatForLeague(league),
+		Scoring:         scoringForLeague(league),
+		AutoConfirmText: autoConfirmText(league),
 		Matches:         matchViews,
 		SetsRange:       setsRange,
 		IsAdmin:         canManage,
@@ -213,15 +260,92 @@ func (s *Server) handleLeagueShow(w http.ResponseWriter, r *http.Request) {
 		PlayerSearch: PlayerSearchView{
 			LeagueID:   league.ID,
 			EmptyQuery: true,
-			CanManage:  canManage,
+			CanManage:  permissions.ManagePlayers,
 		},
 	}
+	if len(league.Divisions) > 0 {
+		view.Divisions = groups
+		view.Unassigned = s.unassignedPlayers(league)
+	} else {
+		view.Standings = groups[0].Standings
+		view.PairStandings = groups[0].PairStandings
+	}
+	if league.Handicap {
+		view.Handicaps = s.handicapViews(league)
+	}
+	view.Roster = s.rosterViews(league)
+	view.Withdrawals = s.withdrawalViews(league)
+	view.PolicyText = withdrawalPolicyLabel(league.WithdrawalPolicy)
+	view.VisibilityText = visibilityLabel(league.Visibility)
+	view.CapacityText = capacityLabel(league)
+	view.IsOwner = canOwnLeague(league, currentUser)
+	view.IsSuperAdmin = isSuperAdmin(currentUser)
+	view.OwnershipCandidates = s.ownershipCandidates(league)
+	if transfer := league.OwnershipTransfer; transfer != nil && canBecomeOwner(league, transfer.ToID) {
+		view.PendingOwner, _ = s.store.GetUser(transfer.ToID)
+	}
+	view.Waitlist = s.waitlistViews(league)
+	view.CalendarURL = s.calendarURL(r, currentUser.ID, league.ID)
+	view.Club, _ = s.store.GetClub(league.ClubID)
+	if !league.IsTeamLeague() {
+		view.Schedule = s.leagueSchedule(league, matches, currentUser)
+		view.CanSchedule = isLeaguePlayer(league, currentUser.ID) && !league.IsArchived() && league.Status != model.LeagueStatusFinished
+		if view.CanSchedule {
+			view.ScheduleOpponents = s.scheduleOpponents(league, currentUser)
+			view.Clubs = s.store.ListClubs()
+			view.SlotsRange = buildSetsRange(model.MaxScheduleSlots)
+		}
+	}
+	for _, entry := range view.Waitlist {
+		if entry.Player.ID == currentUser.ID {
+			view.WaitlistEntry = entry
+		}
+	}
+	view.Can = permissions
+	if view.IsOwner {
+		view.PermissionRoles = permissionRoles()
+		view.PermissionRows = permissionMatrix(league)
+	}
+	if permissions.ManagePlayers {
+		view.Invites = s.inviteViews(r, league)
+	}
+	if league.IsTeamLeague() {
+		view.Teams = s.teamViews(league, currentUser)
+		view.TeamCandidates = s.teamCandidates(league)
+		view.Fixtures = s.leagueFixtureViews(league, matches)
+		view.TeamStandings = BuildTeamStandings(league.Teams, view.Fixtures, scoringForLeague(league))
+	}
 	if currentUser.ID != "" && !view.IsAdmin && !view.IsPlayer {
 		view.PendingJoin = s.store.HasPendingJoinRequest(league.ID, currentUser.ID)
 	}
-	if canManage {
+	if snapshot, ok := s.store.GetStandingsSnapshot(league.ID); ok && league.Status == model.LeagueStatusFinished {
+		view.Standings = snapshotStandings(snapshot)
+		view.Divisions = frozenDivisionGroups(snapshot)
+		if len(view.Divisions) > 0 {
+			view.Standings = nil
+		}
+		view.Frozen = true
+		view.FrozenAt = snapshot.CreatedAt.Format("02 Jan 2006")
+		view.Awards = awardViews(snapshot.Awards)
+	}
+	ratings := s.playerRatings()
+	view.Standings = withRatings(view.Standings, ratings)
+	for i := range view.Divisions {
+		view.Divisions[i].Standings = withRatings(view.Divisions[i].Standings, ratings)
+	}
+	view.Seasons = s.leagueSeasons(league)
+	_, view.HasNextSeason = s.nextSeason(league)
+	view.NextSeasonName = fmt.Sprintf("%s – sezon %d", league.Name, seasonNumber(league)+1)
+	if permissions.ManagePlayers {
 		view.JoinRequests = s.leagueJoinRequestViews(league)
 	}
+	if permissions.ResolveDisputes {
+		for _, match := range matches {
+			if awaitsRuling(match) {
+				view.Disputes = append(view.Disputes, s.matchView(match, currentUser))
+			}
+		}
+	}
 	if totalPages > 0 {
 		view.Pages = make([]int, 0, totalPages)
 		for i := 1; i <= totalPages; i++ {
@@ -249,7 +373,7 @@ func (s *Server) handlePlayerSearch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) {
+	if !can(league, currentUser, model.CapManagePlayers) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -268,7 +392,7 @@ func (s *Server) handlePlayerAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) {
+	if !can(league, currentUser, model.CapManagePlayers) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -281,6 +405,10 @@ func (s *Server) handlePlayerAdd(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "brak gracza", http.StatusBadRequest)
 		return
 	}
+	if !isLeaguePlayer(league, userID) && league.OpenSlots() == 0 {
+		http.Error(w, fmt.Sprintf("liga jest pełna (limit %d graczy)", league.MaxPlayers), http.StatusBadRequest)
+		return
+	}
 	if err := s.store.AddPlayerToLeague(league.ID, userID); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -306,7 +434,7 @@ func (s *Server) handleLeagueAdminAdd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) {
+	if !can(league, currentUser, model.CapManageAdmins) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -340,7 +468,7 @@ func (s *Server) handleLeagueAdminRoleUpdate(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) {
+	if !can(league, currentUser, model.CapManageAdmins) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -373,7 +501,7 @@ func (s *Server) handleLeagueAdminRemove(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) {
+	if !can(league, currentUser, model.CapManageAdmins) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -397,7 +525,7 @@ func (s *Server) handleJoinRequestApprove(w http.ResponseWriter, r *http.Request
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) {
+	if !can(league, currentUser, model.CapManagePlayers) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -410,12 +538,29 @@ func (s *Server) handleJoinRequestApprove(w http.ResponseWriter, r *http.Request
 		http.Error(w, "prośba została już rozpatrzona", http.StatusBadRequest)
 		return
 	}
+	if !isLeaguePlayer(league, req.UserID) && league.OpenSlots() == 0 {
+		if _, err := s.joinLeague(league, req.UserID, currentUser.ID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, "/leagues/"+league.ID+"?notice=request_waitlisted#waitlist", http.StatusSeeOther)
+		return
+	}
 	if !isLeaguePlayer(league, req.UserID) && !isLeagueAdmin(league, req.UserID) {
 		if err := s.store.AddPlayerToLeague(league.ID, req.UserID); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
 	}
+	if divisionID := r.FormValue("division_id"); divisionID != "" {
+		league, _ = s.store.GetLeague(league.ID)
+		if isLeaguePlayer(league, req.UserID) && assignDivision(&league, req.UserID, divisionID) {
+			if err := s.store.UpdateLeague(league); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+	}
 	now := time.Now()
 	req.Status = model.JoinRequestApproved
 	req.DecidedBy = currentUser.ID
@@ -436,7 +581,7 @@ func (s *Server) handleJoinRequestReject(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) {
+	if !can(league, currentUser, model.CapManagePlayers) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -468,7 +613,7 @@ func (s *Server) handleLeagueEnd(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) {
+	if !can(league, currentUser, model.CapEndLeague) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -483,6 +628,10 @@ func (s *Server) handleLeagueEnd(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if err := s.FreezeStandings(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
 	http.Redirect(w, r, "/leagues/"+league.ID, http.StatusSeeOther)
 }
 
@@ -494,7 +643,7 @@ func (s *Server) handleMatchCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	currentUser := s.currentUser(r)
-	if !canManageLeague(league, currentUser) && !isLeaguePlayer(league, currentUser.ID) {
+	if !can(league, currentUser, model.CapEditResults) && !isLeaguePlayer(league, currentUser.ID) {
 		http.Error(w, "brak uprawnień", http.StatusForbidden)
 		return
 	}
@@ -502,6 +651,14 @@ func (s *Server) handleMatchCreate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
 		return
 	}
+	if league.IsArchived() {
+		s.renderMatchFormErrors(w, r, []string{"Liga jest zarchiwizowana – nie można dodawać meczów."})
+		return
+	}
+	if league.IsTeamLeague() {
+		s.renderMatchFormErrors(w, r, []string{"W lidze drużynowej wyniki wpisuje się w meczach drużyn."})
+		return
+	}
 	playerA := r.FormValue("player_a_id")
 	playerB := r.FormValue("player_b_id")
 	if playerA == "" {
@@ -514,7 +671,15 @@ func (s *Server) handleMatchCreate(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "wybierz dwóch różnych graczy", http.StatusBadRequest)
 		return
 	}
-	if !canManageLeague(league, currentUser) {
+	partnerA, partnerB := "", ""
+	if league.Doubles {
+		partnerA, partnerB = r.FormValue("partner_a_id"), r.FormValue("partner_b_id")
+		if !distinctPlayers(playerA, partnerA, playerB, partnerB) {
+			s.renderMatchFormErrors(w, r, []string{"Wybierz czterech różnych graczy: po dwóch na każdą stronę."})
+			return
+		}
+	}
+	if !can(league, currentUser, model.CapEditResults) {
 		if playerA != currentUser.ID {
 			http.Error(w, "gracz A musi być tobą", http.StatusBadRequest)
 			return
@@ -524,9 +689,22 @@ func (s *Server) handleMatchCreate(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	sets := parseSets(r, league.SetsPerMatch)
-	if len(sets) == 0 {
-		http.Error(w, "uzupełnij wynik", http.StatusBadRequest)
+	for _, id := range []string{playerA, partnerA, playerB, partnerB} {
+		if id == "" {
+			continue
+		}
+		if !isLeaguePlayer(league, id) {
+			s.renderMatchFormErrors(w, r, []string{"Wszyscy gracze muszą należeć do ligi."})
+			return
+		}
+		if league.IsSuspended(id) {
+			user, _ := s.store.GetUser(id)
+			s.renderMatchFormErrors(w, r, []string{fmt.Sprintf("%s jest zawieszony(-a) i nie może rozgrywać meczów.", user.FullName())})
+			return
+		}
+	}
+	if !sameDivision(league, playerA, partnerA, playerB, partnerB) {
+		s.renderMatchFormErrors(w, r, []string{"Gracze muszą należeć do tej samej dywizji."})
 		return
 	}
 	match := model.Match{
@@ -534,11 +712,22 @@ func (s *Server) handleMatchCreate(w http.ResponseWriter, r *http.Request) {
 		LeagueID:   league.ID,
 		PlayerAID:  playerA,
 		PlayerBID:  playerB,
-		Sets:       sets,
+		PartnerAID: partnerA,
+		PartnerBID: partnerB,
 		Status:     model.MatchPending,
 		ReportedBy: currentUser.ID,
 		CreatedAt:  time.Now(),
 	}
+	match.HeadStartA, match.HeadStartB = s.headStarts(league, match.SideA(), match.SideB())
+	outcome, conceded := parseMatchOutcome(r)
+	sets, messages := parseMatchResult(r, matchSetFormat(league, match), outcome, conceded)
+	if len(messages) > 0 {
+		s.renderMatchFormErrors(w, r, messages)
+		return
+	}
+	match.Sets = sets
+	match.Outcome = outcome
+	match.ConcededBy = sidePlayerID(conceded, playerA, playerB)
 	if _, err := s.store.CreateMatch(match); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -566,6 +755,14 @@ func (s *Server) handleMatchConfirm(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "nie możesz potwierdzić własnego wyniku", http.StatusForbidden)
 		return
 	}
+	if !canConfirmResult(match.SideA(), match.SideB(), match.ReportedBy, currentUser.ID) {
+		http.Error(w, "wynik potwierdza gracz drugiej strony", http.StatusForbidden)
+		return
+	}
+	if match.Status != model.MatchPending {
+		http.Error(w, "mecz nie oczekuje na potwierdzenie", http.StatusBadRequest)
+		return
+	}
 	match.Status = model.MatchConfirmed
 	match.ConfirmedBy = currentUser.ID
 	if err := s.store.UpdateMatch(match); err != nil {
@@ -583,62 +780,48 @@ func (s *Server) handleMatchConfirm(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/leagues/"+match.LeagueID, http.StatusSeeOther)
 }
 
-func (s *Server) handleMatchReject(w http.ResponseWriter, r *http.Request) {
-	matchID := chi.URLParam(r, "matchID")
-	match, ok := s.store.GetMatch(matchID)
-	if !ok {
-		http.NotFound(w, r)
-		return
-	}
-	currentUser := s.currentUser(r)
-	if match.ReportedBy == currentUser.ID {
-		http.Error(w, "nie możesz odrzucić własnego wyniku", http.StatusForbidden)
+func (s *Server) renderMatchFormErrors(w http.ResponseWriter, r *http.Request, messages []string) {
+	if !isHTMX(r) {
+		http.Error(w, strings.Join(messages, "\n"), http.StatusBadRequest)
 		return
 	}
-	match.Status = model.MatchRejected
-	if err := s.store.UpdateMatch(match); err != nil {
+	w.Header().Set("HX-Retarget", "#match-form-errors")
+	w.Header().Set("HX-Reswap", "innerHTML")
+	if err := s.templates.RenderPartial(w, "form_errors.html", messages); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	if isHTMX(r) {
-		view := s.matchView(match, currentUser)
-		if err := s.templates.RenderPartial(w, "match_row.html", view); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		}
-		return
 	}
-	http.Redirect(w, r, "/leagues/"+match.LeagueID, http.StatusSeeOther)
 }
 
 func (s *Server) matchView(match model.Match, currentUser model.User) MatchView {
-	playerA, _ := s.store.GetUser(match.PlayerAID)
-	playerB, _ := s.store.GetUser(match.PlayerBID)
+	playerA := s.sideUser(match.SideA())
+	playerB := s.sideUser(match.SideB())
 
-	scoreParts := make([]string, 0, len(match.Sets))
-	for _, set := range match.Sets {
-		scoreParts = append(scoreParts, fmt.Sprintf("%d:%d", set.A, set.B))
+	conceded := playerA
+	if match.ConcededBy == match.PlayerBID {
+		conceded = playerB
+	}
+	scoreLine := formatScoreLine(match.Sets, match.Outcome, conceded)
+	if match.Status == model.MatchScheduled && match.FixtureID == "" {
+		scoreLine = scheduleLabel(match)
 	}
-	scoreLine := strings.Join(scoreParts, ", ")
 
-	statusText := map[model.MatchStatus]string{
-		model.MatchScheduled: "Zaplanowany",
-		model.MatchPending:   "Oczekuje na potwierdzenie",
-		model.MatchConfirmed: "Potwierdzony",
-		model.MatchRejected:  "Odrzucony",
-	}[match.Status]
+	statusText := matchStatusText(match.Status)
+	if match.Status == model.MatchConfirmed && match.AutoConfirmed {
+		statusText = "Potwierdzony automatycznie"
+	}
 
-	canConfirm := match.Status == model.MatchPending && match.ReportedBy != currentUser.ID && (currentUser.ID == match.PlayerAID || currentUser.ID == match.PlayerBID)
+	canConfirm := match.Status == model.MatchPending && canConfirmResult(match.SideA(), match.SideB(), match.ReportedBy, currentUser.ID)
 	canReject := canConfirm
 
 	return MatchView{
-		Match:      match,
-		PlayerA:    playerA,
-		PlayerB:    playerB,
-		ScoreLine:  scoreLine,
-		CanConfirm: canConfirm,
-		CanReject:  canReject,
-		StatusText: statusText,
+		Match:         match,
+		PlayerA:       playerA,
+		PlayerB:       playerB,
+		ScoreLine:     scoreLine,
+		HeadStartText: headStartLabel(match, playerA, playerB),
+		CanConfirm:    canConfirm,
+		CanReject:     canReject,
+		StatusText:    statusText,
 	}
 }
 
@@ -714,15 +897,14 @@ func (s *Server) leaguesForUser(userID string) []model.League {
 	return filtered
 }
 
-func (s *Server) searchLeagues(query string) []model.League {
-	leagues := s.store.ListLeagues()
-	if query == "" {
-		return leagues
-	}
+func (s *Server) searchLeagues(query string, currentUser model.User) []model.League {
 	results := []model.League{}
 	lower := strings.ToLower(query)
-	for _, league := range leagues {
-		if strings.Contains(strings.ToLower(league.Name), lower) ||
+	for _, league := range s.store.ListLeagues() {
+		if !canListLeague(league, currentUser) {
+			continue
+		}
+		if query == "" || strings.Contains(strings.ToLower(league.Name), lower) ||
 			strings.Contains(strings.ToLower(league.Description), lower) ||
 			strings.Contains(strings.ToLower(league.Location), lower) {
 			results = append(results, league)
@@ -733,7 +915,7 @@ func (s *Server) searchLeagues(query string) []model.League {
 
 func (s *Server) leagueSearchView(query string, currentUser model.User, page int) LeagueSearchView {
 	const pageSize = 20
-	results := s.searchLeagues(query)
+	results := s.searchLeagues(query, currentUser)
 	total := len(results)
 	totalPages := int(math.Ceil(float64(total) / float64(pageSize)))
 	if totalPages == 0 {
@@ -788,13 +970,7 @@ func (s *Server) leagueSearchView(query string, currentUser model.User, page int
 }
 
 func (s *Server) leaguePlayers(league model.League) []model.User {
-	players := make([]model.User, 0, len(league.PlayerIDs))
-	for _, playerID := range league.PlayerIDs {
-		if u, ok := s.store.GetUser(playerID); ok {
-			players = append(players, u)
-		}
-	}
-	return players
+	return s.usersByID(league.PlayerIDs)
 }
 
 func (s *Server) playerSearchView(league model.League, currentUser model.User, query string, includePanel bool) PlayerSearchView {
@@ -806,6 +982,7 @@ func (s *Server) playerSearchView(league model.League, currentUser model.User, q
 
 	results := []PlayerSearchResult{}
 	if query != "" {
+		ratings := s.playerRatings()
 		lowerQuery := strings.ToLower(query)
 		for _, user := range s.store.ListUsers() {
 			name := strings.ToLower(user.FullName())
@@ -817,6 +994,7 @@ func (s *Server) playerSearchView(league model.League, currentUser model.User, q
 			results = append(results, PlayerSearchResult{
 				User:     user,
 				InLeague: exists,
+				Rating:   ratings[user.ID],
 			})
 		}
 	}
@@ -826,7 +1004,7 @@ func (s *Server) playerSearchView(league model.League, currentUser model.User, q
 		Query:      query,
 		Results:    results,
 		EmptyQuery: query == "",
-		CanManage:  canManageLeague(league, currentUser),
+		CanManage:  can(league, currentUser, model.CapManagePlayers),
 	}
 	if includePanel {
 		view.IncludePanel = true
@@ -915,16 +1093,6 @@ func redirectBack(w http.ResponseWriter, r *http.Request, fallback string, notic
 	http.Redirect(w, r, fallback, http.StatusSeeOther)
 }
 
-func leagueStatusForDates(start time.Time, end *time.Time, now time.Time) model.LeagueStatus {
-	if end != nil && end.Before(now) {
-		return model.LeagueStatusFinished
-	}
-	if start.After(now) {
-		return model.LeagueStatusUpcoming
-	}
-	return model.LeagueStatusActive
-}
-
 func parseLeagueDate(value string) (time.Time, error) {
 	value = strings.TrimSpace(value)
 	if value == "" {
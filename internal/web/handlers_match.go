@@ -0,0 +1,500 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/autoconfirm"
+	"sqoush-app/internal/model"
+)
+
+func (s *Server) handleMatchShow(w http.ResponseWriter, r *http.Request) {
+	match, league, ok := s.matchWithLeague(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	view := s.matchPageView(r, league, match, s.currentUser(r))
+	if err := s.templates.Render(w, "match.html", view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) handleMatchEdit(w http.ResponseWriter, r *http.Request) {
+	match, league, ok := s.matchWithLeague(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !can(league, currentUser, model.CapEditResults) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if !correctableMatch(match) {
+		http.Error(w, "tego meczu nie można korygować", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	status := parseMatchStatus(r.FormValue("status"))
+	outcome, conceded := parseMatchOutcome(r)
+	sets, messages := parseMatchResult(r, matchSetFormat(league, match), outcome, conceded)
+	if reason == "" {
+		messages = append([]string{"Podaj powód korekty wyniku."}, messages...)
+	}
+	if status == "" {
+		messages = append(messages, "Wybierz status meczu.")
+	}
+	if len(messages) > 0 {
+		view := s.matchPageView(r, league, match, currentUser)
+		view.EditErrors = messages
+		view.Reason = reason
+		s.renderMatchPageError(w, view)
+		return
+	}
+
+	if err := s.ensureReportedRevision(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	match.Sets = sets
+	match.Outcome = outcome
+	match.ConcededBy = sidePlayerID(conceded, match.PlayerAID, match.PlayerBID)
+	match.Status = status
+	match.AutoConfirmed = false
+	match.Dispute = nil
+	switch {
+	case status != model.MatchConfirmed:
+		match.ConfirmedBy = ""
+	case match.ConfirmedBy == "":
+		match.ConfirmedBy = currentUser.ID
+	}
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := s.store.CreateMatchRevision(matchRevisionFrom(match, model.RevisionCorrected, currentUser.ID, reason)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=match_corrected", http.StatusSeeOther)
+}
+
+func (s *Server) handleMatchReject(w http.ResponseWriter, r *http.Request) {
+	match, league, ok := s.matchWithLeague(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if match.ReportedBy == currentUser.ID {
+		http.Error(w, "nie możesz odrzucić własnego wyniku", http.StatusForbidden)
+		return
+	}
+	if !canDisputeMatch(match, currentUser) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if match.Status != model.MatchPending {
+		http.Error(w, "mecz nie oczekuje na potwierdzenie", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	outcome, conceded := parseMatchOutcome(r)
+	sets, messages := parseMatchResult(r, matchSetFormat(league, match), outcome, conceded)
+	if len(messages) > 0 {
+		view := s.matchPageView(r, league, match, currentUser)
+		view.DisputeErrors = messages
+		view.Reason = reason
+		s.renderMatchPageError(w, view)
+		return
+	}
+
+	if err := s.ensureReportedRevision(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	match.Status = model.MatchDisputed
+	match.Dispute = &model.MatchDispute{
+		RaisedBy:   currentUser.ID,
+		Sets:       sets,
+		Outcome:    outcome,
+		ConcededBy: sidePlayerID(conceded, match.PlayerAID, match.PlayerBID),
+		CreatedAt:  time.Now(),
+	}
+	if reason == "" {
+		reason = "Odrzucenie wyniku z propozycją"
+	}
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := s.store.CreateMatchRevision(disputeRevision(match, currentUser.ID, reason)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=match_disputed", http.StatusSeeOther)
+}
+
+func (s *Server) handleMatchDisputeAccept(w http.ResponseWriter, r *http.Request) {
+	match, _, ok := s.matchWithLeague(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !canAnswerDispute(match, currentUser) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	match.Sets = match.Dispute.Sets
+	match.Outcome = match.Dispute.Outcome
+	match.ConcededBy = match.Dispute.ConcededBy
+	match.Status = model.MatchConfirmed
+	match.ConfirmedBy = match.Dispute.RaisedBy
+	match.Dispute = nil
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := s.store.CreateMatchRevision(matchRevisionFrom(match, model.RevisionDisputeAccepted, currentUser.ID, "Zgłaszający zaakceptował propozycję przeciwnika")); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=dispute_accepted", http.StatusSeeOther)
+}
+
+func (s *Server) handleMatchDisputeEscalate(w http.ResponseWriter, r *http.Request) {
+	match, _, ok := s.matchWithLeague(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !canAnswerDispute(match, currentUser) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	match.Dispute.Escalated = true
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := s.store.CreateMatchRevision(matchRevisionFrom(match, model.RevisionDisputeEscalated, currentUser.ID, "Zgłaszający podtrzymał swój wynik")); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=dispute_escalated", http.StatusSeeOther)
+}
+
+func (s *Server) handleMatchDisputeResolve(w http.ResponseWriter, r *http.Request) {
+	match, league, ok := s.matchWithLeague(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !can(league, currentUser, model.CapResolveDisputes) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if !awaitsRuling(match) {
+		http.Error(w, "spór nie został przekazany administratorom", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+
+	reason := strings.TrimSpace(r.FormValue("reason"))
+	messages := []string{}
+	if reason == "" {
+		messages = append(messages, "Podaj uzasadnienie rozstrzygnięcia.")
+	}
+	switch r.FormValue("decision") {
+	case "reported":
+	case "proposed":
+		match.Sets = match.Dispute.Sets
+		match.Outcome = match.Dispute.Outcome
+		match.ConcededBy = match.Dispute.ConcededBy
+	case "ruling":
+		outcome, conceded := parseMatchOutcome(r)
+		sets, setMessages := parseMatchResult(r, matchSetFormat(league, match), outcome, conceded)
+		messages = append(messages, setMessages...)
+		match.Sets = sets
+		match.Outcome = outcome
+		match.ConcededBy = sidePlayerID(conceded, match.PlayerAID, match.PlayerBID)
+	default:
+		messages = append(messages, "Wybierz rozstrzygnięcie sporu.")
+	}
+	if len(messages) > 0 {
+		original, _ := s.store.GetMatch(match.ID)
+		view := s.matchPageView(r, league, original, currentUser)
+		view.ResolveErrors = messages
+		view.Reason = reason
+		s.renderMatchPageError(w, view)
+		return
+	}
+
+	match.Status = model.MatchConfirmed
+	match.ConfirmedBy = currentUser.ID
+	match.Dispute = nil
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if _, err := s.store.CreateMatchRevision(matchRevisionFrom(match, model.RevisionDisputeResolved, currentUser.ID, reason)); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=dispute_resolved", http.StatusSeeOther)
+}
+
+func (s *Server) matchWithLeague(r *http.Request) (model.Match, model.League, bool) {
+	match, ok := s.store.GetMatch(chi.URLParam(r, "matchID"))
+	if !ok {
+		return model.Match{}, model.League{}, false
+	}
+	league, ok := s.store.GetLeague(match.LeagueID)
+	if !ok || !canViewLeague(league, s.currentUser(r)) {
+		return model.Match{}, model.League{}, false
+	}
+	return match, league, true
+}
+
+func (s *Server) renderMatchPageError(w http.ResponseWriter, view MatchPageView) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := s.templates.Render(w, "match.html", view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Server) matchPageView(r *http.Request, league model.League, match model.Match, currentUser model.User) MatchPageView {
+	view := MatchPageView{
+		BaseView: BaseView{
+			Title:           "Mecz ligowy",
+			CurrentUser:     currentUser,
+			Users:           s.store.ListUsers(),
+			IsAuthenticated: true,
+			IsDev:           isDevMode(),
+			FlashSuccess:    flashMessage(r.URL.Query().Get("notice")),
+		},
+		League:           league,
+		Match:            s.matchView(match, currentUser),
+		SetFormat:        setFormatForLeague(league),
+		CanEdit:          can(league, currentUser, model.CapEditResults) && correctableMatch(match),
+		CanDispute:       match.Status == model.MatchPending && match.ReportedBy != currentUser.ID && canDisputeMatch(match, currentUser),
+		CanAnswerDispute: canAnswerDispute(match, currentUser),
+	}
+	view.CurrentResult = MatchResultFieldsView{
+		PlayerA:      view.Match.PlayerA,
+		PlayerB:      view.Match.PlayerB,
+		Outcome:      normalizeOutcome(match.Outcome),
+		ConcededSide: concededSide(match.PlayerAID, match.PlayerBID, match.ConcededBy),
+		Sets:         matchSetInputs(match.Sets, league.SetsPerMatch),
+	}
+	view.EmptyResult = MatchResultFieldsView{
+		PlayerA: view.Match.PlayerA,
+		PlayerB: view.Match.PlayerB,
+		Outcome: model.OutcomeNormal,
+		Sets:    matchSetInputs(nil, league.SetsPerMatch),
+	}
+	view.CanResolve = can(league, currentUser, model.CapResolveDisputes) && awaitsRuling(match)
+	if match.Status == model.MatchScheduled && match.FixtureID == "" {
+		view.CanSchedule = match.Involves(currentUser.ID) && !league.IsArchived()
+		view.Proposal = s.scheduleProposalView(match, currentUser)
+		view.ScheduleLabel = scheduleLabel(match)
+		view.SlotsRange = buildSetsRange(model.MaxScheduleSlots)
+		view.Clubs = s.store.ListClubs()
+		if view.CanSchedule {
+			view.Booking = s.matchBookingView(match, currentUser, time.Now())
+		}
+	}
+	if match.Status == model.MatchPending && autoconfirm.Enabled(league) {
+		view.ConfirmDeadline = autoconfirm.Deadline(league, match).Format("02 Jan 2006 15:04")
+		view.AutoConfirmText = autoConfirmText(league)
+	}
+	if match.Dispute != nil {
+		view.Dispute = s.matchDisputeView(match)
+	}
+	for _, revision := range s.store.ListMatchRevisions(match.ID) {
+		view.Revisions = append(view.Revisions, s.matchRevisionView(match, revision))
+	}
+	return view
+}
+
+func (s *Server) matchDisputeView(match model.Match) *MatchDisputeView {
+	raisedBy, _ := s.store.GetUser(match.Dispute.RaisedBy)
+	conceded := s.concededUser(match, match.Dispute.ConcededBy)
+	return &MatchDisputeView{
+		Dispute:        *match.Dispute,
+		RaisedBy:       raisedBy,
+		ScoreLine:      formatScoreLine(match.Dispute.Sets, match.Dispute.Outcome, conceded),
+		CreatedAtLabel: match.Dispute.CreatedAt.Format("02 Jan 2006 15:04"),
+	}
+}
+
+func (s *Server) matchRevisionView(match model.Match, revision model.MatchRevision) MatchRevisionView {
+	changedBy := "System"
+	if user, ok := s.store.GetUser(revision.ChangedBy); ok {
+		changedBy = user.FullName()
+	}
+	conceded := s.concededUser(match, revision.ConcededBy)
+	return MatchRevisionView{
+		Revision:       revision,
+		ChangedBy:      changedBy,
+		ActionText:     revisionActionText(revision.Action),
+		ScoreLine:      formatScoreLine(revision.Sets, revision.Outcome, conceded),
+		StatusText:     matchStatusText(revision.Status),
+		CreatedAtLabel: revision.CreatedAt.Format("02 Jan 2006 15:04"),
+	}
+}
+
+// ensureReportedRevision stores the result as originally reported before the
+// first change, so the history always starts from the player's report.
+func (s *Server) ensureReportedRevision(match model.Match) error {
+	if len(s.store.ListMatchRevisions(match.ID)) > 0 {
+		return nil
+	}
+	original := matchRevisionFrom(match, model.RevisionReported, match.ReportedBy, "Zgłoszenie wyniku")
+	original.Status = model.MatchPending
+	original.CreatedAt = match.ReportTime()
+	_, err := s.store.CreateMatchRevision(original)
+	return err
+}
+
+func matchRevisionFrom(match model.Match, action model.MatchRevisionAction, changedBy string, reason string) model.MatchRevision {
+	return model.MatchRevision{
+		MatchID:    match.ID,
+		Action:     action,
+		Sets:       append([]model.SetScore{}, match.Sets...),
+		Outcome:    normalizeOutcome(match.Outcome),
+		ConcededBy: match.ConcededBy,
+		Status:     match.Status,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func disputeRevision(match model.Match, changedBy string, reason string) model.MatchRevision {
+	revision := matchRevisionFrom(match, model.RevisionDisputed, changedBy, reason)
+	revision.Sets = append([]model.SetScore{}, match.Dispute.Sets...)
+	revision.Outcome = normalizeOutcome(match.Dispute.Outcome)
+	revision.ConcededBy = match.Dispute.ConcededBy
+	return revision
+}
+
+func parseMatchResult(r *http.Request, format SetFormat, outcome model.MatchOutcome, conceded string) ([]model.SetScore, []string) {
+	sets, setErrs := parseSets(r, format.BestOf)
+	if len(setErrs) == 0 {
+		setErrs = validateMatchResult(sets, outcome, conceded, format)
+	}
+	return sets, setErrorMessages(setErrs)
+}
+
+func canDisputeMatch(match model.Match, user model.User) bool {
+	return canConfirmResult(match.SideA(), match.SideB(), match.ReportedBy, user.ID)
+}
+
+// awaitsRuling reports whether the dispute has reached the league admins:
+// the reporter stood by their score, or nobody confirmed it in time.
+func awaitsRuling(match model.Match) bool {
+	return match.Status == model.MatchDisputed && match.Dispute != nil && match.Dispute.Escalated
+}
+
+func canAnswerDispute(match model.Match, user model.User) bool {
+	return match.Status == model.MatchDisputed && match.Dispute != nil && !match.Dispute.Escalated && user.ID != "" && user.ID == match.ReportedBy
+}
+
+func matchSetInputs(sets []model.SetScore, maxSets int) []SetInputView {
+	inputs := make([]SetInputView, 0, maxSets)
+	for _, number := range buildSetsRange(maxSets) {
+		input := SetInputView{Number: number}
+		if number <= len(sets) {
+			input.A = strconv.Itoa(sets[number-1].A)
+			input.B = strconv.Itoa(sets[number-1].B)
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs
+}
+
+// correctableMatch reports whether admins may correct the result directly.
+// Scheduled and voided matches have no result to correct, and disputes go
+// through resolution instead.
+func correctableMatch(match model.Match) bool {
+	switch match.Status {
+	case model.MatchPending, model.MatchConfirmed, model.MatchRejected:
+		return true
+	}
+	return false
+}
+
+func parseMatchStatus(value string) model.MatchStatus {
+	switch status := model.MatchStatus(strings.TrimSpace(value)); status {
+	case model.MatchPending, model.MatchConfirmed, model.MatchRejected:
+		return status
+	}
+	return ""
+}
+
+func matchStatusText(status model.MatchStatus) string {
+	return map[model.MatchStatus]string{
+		model.MatchScheduled: "Zaplanowany",
+		model.MatchPending:   "Oczekuje na potwierdzenie",
+		model.MatchConfirmed: "Potwierdzony",
+		model.MatchRejected:  "Odrzucony",
+		model.MatchDisputed:  "Sporny",
+		model.MatchVoided:    "Anulowany",
+	}[status]
+}
+
+func autoConfirmText(league model.League) string {
+	if !autoconfirm.Enabled(league) {
+		return ""
+	}
+	hours := int(autoconfirm.Window(league).Hours())
+	if league.AutoConfirmAction == model.AutoConfirmEscalate {
+		return fmt.Sprintf("Wynik niepotwierdzony w ciągu %d h trafia do administratorów ligi.", hours)
+	}
+	return fmt.Sprintf("Wynik niepotwierdzony w ciągu %d h zostanie potwierdzony automatycznie.", hours)
+}
+
+func revisionActionText(action model.MatchRevisionAction) string {
+	switch action {
+	case model.RevisionReported:
+		return "Zgłoszenie wyniku"
+	case model.RevisionDisputed:
+		return "Odrzucenie z propozycją wyniku"
+	case model.RevisionDisputeAccepted:
+		return "Akceptacja propozycji"
+	case model.RevisionDisputeEscalated:
+		return "Przekazanie sporu administratorowi"
+	case model.RevisionDisputeResolved:
+		return "Rozstrzygnięcie sporu"
+	case model.RevisionAutoConfirmed:
+		return "Automatyczne potwierdzenie"
+	case model.RevisionAutoEscalated:
+		return "Automatyczne przekazanie administratorom"
+	case model.RevisionVoided:
+		return "Anulowanie po odejściu gracza"
+	}
+	return "Korekta administratora"
+}
@@ -0,0 +1,148 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/model"
+)
+
+// handleOwnershipNominate lets the owner offer the league to one of its
+// admins. Nothing changes until the nominee accepts.
+func (s *Server) handleOwnershipNominate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.ownedLeague(w, r)
+	if !ok {
+		return
+	}
+	adminID := strings.TrimSpace(r.FormValue("admin_id"))
+	if !canBecomeOwner(league, adminID) {
+		http.Error(w, "właścicielem może zostać tylko inny administrator ligi", http.StatusBadRequest)
+		return
+	}
+	league.OwnershipTransfer = &model.OwnershipTransfer{
+		ToID:      adminID,
+		ByID:      s.currentUser(r).ID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=ownership_nominated#admins", http.StatusSeeOther)
+}
+
+func (s *Server) handleOwnershipCancel(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.ownedLeague(w, r)
+	if !ok {
+		return
+	}
+	league.OwnershipTransfer = nil
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=ownership_cancelled#admins", http.StatusSeeOther)
+}
+
+// handleOwnershipRespond is the nominee's answer to a pending transfer.
+func (s *Server) handleOwnershipRespond(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	transfer := league.OwnershipTransfer
+	if transfer == nil || transfer.ToID != currentUser.ID {
+		http.Error(w, "nie masz oczekującej propozycji przejęcia tej ligi", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	notice := "ownership_declined"
+	if r.FormValue("action") == "accept" {
+		if !canBecomeOwner(league, currentUser.ID) {
+			http.Error(w, "nie jesteś już administratorem tej ligi", http.StatusBadRequest)
+			return
+		}
+		transferOwnership(&league, currentUser.ID)
+		notice = "ownership_accepted"
+	}
+	league.OwnershipTransfer = nil
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice="+notice+"#admins", http.StatusSeeOther)
+}
+
+// handleOwnershipOverride hands the league to another admin straight away,
+// for when the owner is gone and cannot nominate anyone.
+func (s *Server) handleOwnershipOverride(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if !isSuperAdmin(s.currentUser(r)) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	adminID := strings.TrimSpace(r.FormValue("admin_id"))
+	if !canBecomeOwner(league, adminID) {
+		http.Error(w, "właścicielem może zostać tylko inny administrator ligi", http.StatusBadRequest)
+		return
+	}
+	transferOwnership(&league, adminID)
+	league.OwnershipTransfer = nil
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=ownership_accepted#admins", http.StatusSeeOther)
+}
+
+func canBecomeOwner(league model.League, userID string) bool {
+	if userID == "" || userID == league.OwnerID {
+		return false
+	}
+	_, ok := league.AdminRoles[userID]
+	return ok
+}
+
+// transferOwnership makes newOwnerID the owner. The previous owner stays on
+// as an admin-player.
+func transferOwnership(league *model.League, newOwnerID string) {
+	previous := league.OwnerID
+	league.OwnerID = newOwnerID
+	if previous == "" {
+		return
+	}
+	if league.AdminRoles == nil {
+		league.AdminRoles = map[string]model.LeagueAdminRole{}
+	}
+	league.AdminRoles[previous] = model.LeagueAdminPlayer
+	if !isLeaguePlayer(*league, previous) {
+		league.PlayerIDs = append(league.PlayerIDs, previous)
+	}
+}
+
+// ownershipCandidates lists the admins the league can be handed to.
+func (s *Server) ownershipCandidates(league model.League) []model.User {
+	candidates := []model.User{}
+	for _, admin := range s.leagueAdmins(league) {
+		if canBecomeOwner(league, admin.User.ID) {
+			candidates = append(candidates, admin.User)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,83 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/model"
+)
+
+// profileRecentGames is how many of the latest results the profile lists.
+const profileRecentGames = 10
+
+func (s *Server) handlePlayerShow(w http.ResponseWriter, r *http.Request) {
+	player, ok := s.store.GetUser(chi.URLParam(r, "userID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	progress := s.playerProgress(player, currentUser, time.Now())
+	view := PlayerProfileView{
+		BaseView: BaseView{
+			Title:           player.FullName(),
+			CurrentUser:     currentUser,
+			Users:           s.store.ListUsers(),
+			IsAuthenticated: currentUser.ID != "",
+			IsDev:           isDevMode(),
+			FlashSuccess:    flashMessage(r.URL.Query().Get("notice")),
+		},
+		Player:        player,
+		Progress:      progress,
+		Leagues:       s.playerLeagues(player, currentUser),
+		RatingChart:   ratingChart(progress),
+		WinRateChart:  winRateChart(progress),
+		PointsChart:   pointsPerSetChart(progress),
+		ProgressURL:   "/players/" + player.ID + "/progress",
+		IsCurrentUser: player.ID == currentUser.ID,
+	}
+	for _, game := range progress.Games {
+		if game.Won {
+			view.Wins++
+		} else {
+			view.Losses++
+		}
+	}
+	recent := slices.Clone(progress.Games)
+	slices.Reverse(recent)
+	view.RecentGames = recent[:min(len(recent), profileRecentGames)]
+	if err := s.templates.Render(w, "player.html", view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handlePlayerProgress serves the series behind the profile charts as JSON.
+func (s *Server) handlePlayerProgress(w http.ResponseWriter, r *http.Request) {
+	player, ok := s.store.GetUser(chi.URLParam(r, "userID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(s.playerProgress(player, s.currentUser(r), time.Now())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// playerLeagues lists the leagues the player plays in that the viewer may
+// see, newest first.
+func (s *Server) playerLeagues(player, viewer model.User) []model.League {
+	leagues := []model.League{}
+	for _, league := range s.store.ListLeagues() {
+		if isLeaguePlayer(league, player.ID) && canListLeague(league, viewer) {
+			leagues = append(leagues, league)
+		}
+	}
+	sort.Slice(leagues, func(i, j int) bool { return leagues[i].StartDate.After(leagues[j].StartDate) })
+	return leagues
+}
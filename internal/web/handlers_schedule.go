@@ -0,0 +1,377 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"sqoush-app/internal/model"
+)
+
+const scheduleTimeLayout = "02 Jan 2006 15:04"
+
+// handleScheduleProposal starts a league match by offering the opponent up
+// to three times. The match has no result until it is played and reported.
+func (s *Server) handleScheduleProposal(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !isLeaguePlayer(league, currentUser.ID) {
+		http.Error(w, "terminy meczów ustalają gracze ligi", http.StatusForbidden)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	if league.IsArchived() || league.IsTeamLeague() || league.Status == model.LeagueStatusFinished {
+		http.Error(w, "w tej lidze nie można umawiać meczów", http.StatusBadRequest)
+		return
+	}
+	match := model.Match{
+		ID:        uuid.NewString(),
+		LeagueID:  league.ID,
+		PlayerAID: currentUser.ID,
+		PlayerBID: r.FormValue("player_b_id"),
+		Status:    model.MatchScheduled,
+		CreatedAt: time.Now(),
+	}
+	if league.Doubles {
+		match.PartnerAID, match.PartnerBID = r.FormValue("partner_a_id"), r.FormValue("partner_b_id")
+	}
+	if message := s.scheduleSidesError(league, match); message != "" {
+		http.Error(w, message, http.StatusBadRequest)
+		return
+	}
+	slots, errs := s.parseScheduleSlots(r, time.Now())
+	if len(errs) > 0 {
+		http.Error(w, strings.Join(errs, " "), http.StatusBadRequest)
+		return
+	}
+	match.Proposal = &model.ScheduleProposal{ProposedBy: currentUser.ID, Slots: slots, CreatedAt: time.Now()}
+	match.HeadStartA, match.HeadStartB = s.headStarts(league, match.SideA(), match.SideB())
+	if _, err := s.store.CreateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=schedule_proposed", http.StatusSeeOther)
+}
+
+func (s *Server) scheduleSidesError(league model.League, match model.Match) string {
+	if league.Doubles {
+		if !distinctPlayers(match.PlayerAID, match.PartnerAID, match.PlayerBID, match.PartnerBID) {
+			return "wybierz czterech różnych graczy: po dwóch na każdą stronę"
+		}
+	} else if match.PlayerBID == "" || match.PlayerBID == match.PlayerAID {
+		return "wybierz przeciwnika"
+	}
+	for _, id := range match.Players() {
+		if !isLeaguePlayer(league, id) {
+			return "wszyscy gracze muszą należeć do ligi"
+		}
+		if league.IsSuspended(id) {
+			user, _ := s.store.GetUser(id)
+			return fmt.Sprintf("%s jest zawieszony(-a) i nie może rozgrywać meczów", user.FullName())
+		}
+	}
+	if !sameDivision(league, match.PlayerAID, match.PartnerAID, match.PlayerBID, match.PartnerBID) {
+		return "gracze muszą należeć do tej samej dywizji"
+	}
+	return ""
+}
+
+// parseScheduleSlots reads the slot_N_at, slot_N_club and slot_N_venue
+// fields. Empty slots are skipped, but at least one future time is required.
+func (s *Server) parseScheduleSlots(r *http.Request, now time.Time) ([]model.ScheduleSlot, []string) {
+	slots := []model.ScheduleSlot{}
+	errs := []string{}
+	for i := 1; i <= model.MaxScheduleSlots; i++ {
+		value := strings.TrimSpace(r.FormValue(fmt.Sprintf("slot_%d_at", i)))
+		if value == "" {
+			continue
+		}
+		at, err := time.ParseInLocation("2006-01-02T15:04", value, time.Local)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Termin %d ma nieprawidłową datę.", i))
+			continue
+		}
+		if !at.After(now) {
+			errs = append(errs, fmt.Sprintf("Termin %d musi być w przyszłości.", i))
+			continue
+		}
+		slot := model.ScheduleSlot{At: at, Venue: strings.TrimSpace(r.FormValue(fmt.Sprintf("slot_%d_venue", i)))}
+		if clubID := r.FormValue(fmt.Sprintf("slot_%d_club", i)); clubID != "" {
+			club, ok := s.store.GetClub(clubID)
+			if !ok {
+				errs = append(errs, fmt.Sprintf("Termin %d wskazuje nieistniejący klub.", i))
+				continue
+			}
+			// The venue field then only narrows the place down, e.g. to a court.
+			slot.ClubID = club.ID
+			slot.Venue = strings.TrimSuffix(club.Name+", "+slot.Venue, ", ")
+		}
+		slots = append(slots, slot)
+	}
+	if len(slots) == 0 && len(errs) == 0 {
+		errs = append(errs, "Zaproponuj przynajmniej jeden termin.")
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i].At.Before(slots[j].At) })
+	return slots, errs
+}
+
+// schedulableMatch loads a league match that is still waiting to be played,
+// for one of its players.
+func (s *Server) schedulableMatch(w http.ResponseWriter, r *http.Request) (model.Match, model.League, bool) {
+	match, league, ok := s.matchWithLeague(r)
+	if !ok {
+		http.NotFound(w, r)
+		return model.Match{}, model.League{}, false
+	}
+	if !match.Involves(s.currentUser(r).ID) {
+		http.Error(w, "terminy meczu ustalają jego gracze", http.StatusForbidden)
+		return model.Match{}, model.League{}, false
+	}
+	if match.Status != model.MatchScheduled || match.FixtureID != "" {
+		http.Error(w, "termin tego meczu nie podlega już zmianom", http.StatusBadRequest)
+		return model.Match{}, model.League{}, false
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return model.Match{}, model.League{}, false
+	}
+	return match, league, true
+}
+
+// handleScheduleCounter replaces the open proposal with new times. It also
+// reopens the negotiation when an agreed date no longer suits a player.
+func (s *Server) handleScheduleCounter(w http.ResponseWriter, r *http.Request) {
+	match, league, ok := s.schedulableMatch(w, r)
+	if !ok {
+		return
+	}
+	currentUser := s.currentUser(r)
+	slots, errs := s.parseScheduleSlots(r, time.Now())
+	if len(errs) > 0 {
+		view := s.matchPageView(r, league, match, currentUser)
+		view.ScheduleErrors = errs
+		s.renderMatchPageError(w, view)
+		return
+	}
+	match.Proposal = &model.ScheduleProposal{ProposedBy: currentUser.ID, Slots: slots, CreatedAt: time.Now()}
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=schedule_proposed", http.StatusSeeOther)
+}
+
+func (s *Server) handleScheduleAccept(w http.ResponseWriter, r *http.Request) {
+	match, _, ok := s.schedulableMatch(w, r)
+	if !ok {
+		return
+	}
+	if !canAnswerProposal(match, s.currentUser(r)) {
+		http.Error(w, "termin zatwierdza druga strona meczu", http.StatusForbidden)
+		return
+	}
+	index, err := strconv.Atoi(r.FormValue("slot"))
+	if err != nil || index < 0 || index >= len(match.Proposal.Slots) {
+		http.Error(w, "wybierz jeden z proponowanych terminów", http.StatusBadRequest)
+		return
+	}
+	// The court booked for the previous date is no longer needed.
+	if err := s.releaseMatchBooking(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	slot := match.Proposal.Slots[index]
+	match.ScheduledAt = &slot.At
+	match.Venue = slot.Venue
+	match.ClubID = slot.ClubID
+	match.Proposal = nil
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=schedule_accepted", http.StatusSeeOther)
+}
+
+// handleScheduleCancel calls the match off. It is voided like the unplayed
+// matches of a player who leaves the league.
+func (s *Server) handleScheduleCancel(w http.ResponseWriter, r *http.Request) {
+	match, league, ok := s.schedulableMatch(w, r)
+	if !ok {
+		return
+	}
+	if err := s.releaseMatchBooking(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	match.Status = model.MatchVoided
+	match.Proposal = nil
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=schedule_cancelled#schedule", http.StatusSeeOther)
+}
+
+// handleScheduledReport records the result of a scheduled match. From there
+// it follows the regular confirmation flow.
+func (s *Server) handleScheduledReport(w http.ResponseWriter, r *http.Request) {
+	match, league, ok := s.schedulableMatch(w, r)
+	if !ok {
+		return
+	}
+	if league.IsArchived() {
+		http.Error(w, "liga jest zarchiwizowana", http.StatusBadRequest)
+		return
+	}
+	if match.ScheduledAt == nil {
+		http.Error(w, "wynik można zgłosić po ustaleniu terminu meczu", http.StatusBadRequest)
+		return
+	}
+	currentUser := s.currentUser(r)
+	outcome, conceded := parseMatchOutcome(r)
+	sets, errs := parseMatchResult(r, matchSetFormat(league, match), outcome, conceded)
+	if len(errs) > 0 {
+		view := s.matchPageView(r, league, match, currentUser)
+		view.ReportErrors = errs
+		s.renderMatchPageError(w, view)
+		return
+	}
+	match.Sets = sets
+	match.Outcome = outcome
+	match.ConcededBy = sidePlayerID(conceded, match.PlayerAID, match.PlayerBID)
+	match.Status = model.MatchPending
+	match.ReportedBy = currentUser.ID
+	match.Proposal = nil
+	reportedAt := time.Now()
+	match.ReportedAt = &reportedAt
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/matches/"+match.ID+"?notice=result_reported", http.StatusSeeOther)
+}
+
+// canAnswerProposal reports whether the user is on the side that did not
+// make the open proposal.
+func canAnswerProposal(match model.Match, user model.User) bool {
+	if match.Proposal == nil {
+		return false
+	}
+	return canConfirmResult(match.SideA(), match.SideB(), match.Proposal.ProposedBy, user.ID)
+}
+
+func scheduleLabel(match model.Match) string {
+	if match.ScheduledAt == nil {
+		return "Ustalanie terminu"
+	}
+	label := match.ScheduledAt.Format(scheduleTimeLayout)
+	if match.Venue != "" {
+		label += ", " + match.Venue
+	}
+	return label
+}
+
+func (s *Server) scheduleProposalView(match model.Match, currentUser model.User) *ScheduleProposalView {
+	if match.Proposal == nil {
+		return nil
+	}
+	proposedBy, _ := s.store.GetUser(match.Proposal.ProposedBy)
+	view := &ScheduleProposalView{
+		ProposedBy: proposedBy,
+		CanAnswer:  canAnswerProposal(match, currentUser),
+	}
+	for i, slot := range match.Proposal.Slots {
+		view.Slots = append(view.Slots, ScheduleSlotView{
+			Index:   i,
+			AtLabel: slot.At.Format(scheduleTimeLayout),
+			Venue:   slot.Venue,
+			ClubID:  slot.ClubID,
+		})
+	}
+	return view
+}
+
+// upcomingMatches lists the user's league matches that are agreed or still
+// being arranged. Agreed ones come first, soonest on top.
+func (s *Server) upcomingMatches(currentUser model.User, now time.Time) []UpcomingMatchView {
+	if currentUser.ID == "" {
+		return nil
+	}
+	views := []UpcomingMatchView{}
+	for _, league := range s.leaguesForUser(currentUser.ID) {
+		if league.IsArchived() {
+			continue
+		}
+		for _, match := range s.store.ListMatches(league.ID) {
+			if match.Status != model.MatchScheduled || match.FixtureID != "" || !match.Involves(currentUser.ID) {
+				continue
+			}
+			if match.ScheduledAt != nil && match.ScheduledAt.Before(startOfDay(now)) && match.Proposal == nil {
+				continue
+			}
+			views = append(views, UpcomingMatchView{
+				Match:      s.matchView(match, currentUser),
+				League:     league,
+				When:       scheduleLabel(match),
+				NeedsReply: canAnswerProposal(match, currentUser),
+			})
+		}
+	}
+	sort.SliceStable(views, func(i, j int) bool {
+		a, b := views[i].Match.Match, views[j].Match.Match
+		if (a.ScheduledAt == nil) != (b.ScheduledAt == nil) {
+			return a.ScheduledAt != nil
+		}
+		if a.ScheduledAt != nil && !a.ScheduledAt.Equal(*b.ScheduledAt) {
+			return a.ScheduledAt.Before(*b.ScheduledAt)
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return views
+}
+
+// leagueSchedule lists the league's matches with an agreed date that have
+// not been reported yet.
+func (s *Server) leagueSchedule(league model.League, matches []model.Match, currentUser model.User) []MatchView {
+	scheduled := []model.Match{}
+	for _, match := range matches {
+		if match.Status == model.MatchScheduled && match.FixtureID == "" && match.ScheduledAt != nil {
+			scheduled = append(scheduled, match)
+		}
+	}
+	sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].ScheduledAt.Before(*scheduled[j].ScheduledAt) })
+	views := make([]MatchView, 0, len(scheduled))
+	for _, match := range scheduled {
+		views = append(views, s.matchView(match, currentUser))
+	}
+	return views
+}
+
+// scheduleOpponents lists the players the user can propose a match to.
+func (s *Server) scheduleOpponents(league model.League, currentUser model.User) []model.User {
+	opponents := []model.User{}
+	for _, player := range s.matchPlayers(league, currentUser, false) {
+		if player.ID != currentUser.ID {
+			opponents = append(opponents, player)
+		}
+	}
+	return opponents
+}
+
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
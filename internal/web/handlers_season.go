@@ -0,0 +1,189 @@
+package web
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"sqoush-app/internal/model"
+)
+
+func (s *Server) handleLeagueNextSeason(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !can(league, currentUser, model.CapEndLeague) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if next, ok := s.nextSeason(league); ok {
+		http.Redirect(w, r, "/leagues/"+next.ID, http.StatusSeeOther)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	season := seasonNumber(league) + 1
+	name := strings.TrimSpace(r.FormValue("name"))
+	if name == "" {
+		name = fmt.Sprintf("%s – sezon %d", league.Name, season)
+	}
+	startDate, err := parseLeagueDate(r.FormValue("start_date"))
+	if err != nil {
+		http.Error(w, "nieprawidłowa data startu", http.StatusBadRequest)
+		return
+	}
+	endDate, err := parseOptionalLeagueDate(r.FormValue("end_date"))
+	if err != nil {
+		http.Error(w, "nieprawidłowa data końca", http.StatusBadRequest)
+		return
+	}
+	if endDate != nil && endDate.Before(startDate) {
+		http.Error(w, "data końca musi być po dacie startu", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	if league.Status != model.LeagueStatusFinished {
+		league.Status = model.LeagueStatusFinished
+		if league.EndDate == nil || league.EndDate.After(now) {
+			league.EndDate = &now
+		}
+		if err := s.store.UpdateLeague(league); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := s.FreezeStandings(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	adminRoles := make(map[string]model.LeagueAdminRole, len(league.AdminRoles))
+	for id, role := range league.AdminRoles {
+		adminRoles[id] = role
+	}
+	playerIDs := []string{}
+	if r.FormValue("copy_players") != "" {
+		playerIDs = append(playerIDs, league.PlayerIDs...)
+	} else {
+		for _, id := range league.PlayerIDs {
+			if id == league.OwnerID || adminRoles[id] == model.LeagueAdminPlayer {
+				playerIDs = append(playerIDs, id)
+			}
+		}
+	}
+	next := model.League{
+		ID:                uuid.NewString(),
+		Name:              name,
+		Description:       league.Description,
+		Location:          league.Location,
+		ClubID:            league.ClubID,
+		OwnerID:           league.OwnerID,
+		AdminRoles:        adminRoles,
+		Permissions:       maps.Clone(league.Permissions),
+		PlayerIDs:         playerIDs,
+		SetsPerMatch:      league.SetsPerMatch,
+		PointsPerSet:      league.PointsPerSet,
+		Scoring:           league.Scoring,
+		TieBreakers:       append([]model.TieBreakRule{}, league.TieBreakers...),
+		ConfirmationHours: league.ConfirmationHours,
+		AutoConfirmAction: league.AutoConfirmAction,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		Status:            model.LeagueStatusForDates(startDate, endDate, now),
+		Season:            season,
+		PreviousSeasonID:  league.ID,
+		PromotionCount:    league.PromotionCount,
+		Doubles:           league.Doubles,
+		Competition:       league.Competition,
+		RubbersPerFixture: league.RubbersPerFixture,
+		Teams:             nextSeasonTeams(league.Teams, playerIDs),
+		Handicap:          league.Handicap,
+		Handicaps:         maps.Clone(league.Handicaps),
+		WithdrawalPolicy:  league.WithdrawalPolicy,
+		Visibility:        league.Visibility,
+		MaxPlayers:        league.MaxPlayers,
+		CreatedAt:         now,
+	}
+	if len(league.Divisions) > 0 {
+		snapshot, _ := s.store.GetStandingsSnapshot(league.ID)
+		next.Divisions = keepDivisionPlayers(promoteRelegate(league.Divisions, snapshot, league.PromotionCount), playerIDs)
+	}
+	if _, err := s.store.CreateLeague(next); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+next.ID+"?notice=season_started", http.StatusSeeOther)
+}
+
+func (s *Server) nextSeason(league model.League) (model.League, bool) {
+	for _, candidate := range s.store.ListLeagues() {
+		if candidate.PreviousSeasonID == league.ID {
+			return candidate, true
+		}
+	}
+	return model.League{}, false
+}
+
+// leagueSeasons walks the chain of linked seasons in both directions and
+// returns it ordered from the first season.
+func (s *Server) leagueSeasons(league model.League) []SeasonLinkView {
+	byID := map[string]model.League{}
+	nextByID := map[string]model.League{}
+	for _, candidate := range s.store.ListLeagues() {
+		byID[candidate.ID] = candidate
+		if candidate.PreviousSeasonID != "" {
+			nextByID[candidate.PreviousSeasonID] = candidate
+		}
+	}
+	chain := []model.League{league}
+	seen := map[string]bool{league.ID: true}
+	for current := league; current.PreviousSeasonID != ""; {
+		previous, ok := byID[current.PreviousSeasonID]
+		if !ok || seen[previous.ID] {
+			break
+		}
+		seen[previous.ID] = true
+		chain = append(chain, previous)
+		current = previous
+	}
+	for current := league; ; {
+		next, ok := nextByID[current.ID]
+		if !ok || seen[next.ID] {
+			break
+		}
+		seen[next.ID] = true
+		chain = append(chain, next)
+		current = next
+	}
+	sort.Slice(chain, func(i, j int) bool {
+		return seasonNumber(chain[i]) < seasonNumber(chain[j])
+	})
+	seasons := make([]SeasonLinkView, 0, len(chain))
+	for _, item := range chain {
+		seasons = append(seasons, SeasonLinkView{
+			LeagueID: item.ID,
+			Label:    fmt.Sprintf("Sezon %d", seasonNumber(item)),
+			Current:  item.ID == league.ID,
+		})
+	}
+	return seasons
+}
+
+func seasonNumber(league model.League) int {
+	if league.Season < 1 {
+		return 1
+	}
+	return league.Season
+}
@@ -0,0 +1,198 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/model"
+)
+
+func canOwnLeague(league model.League, user model.User) bool {
+	return isSuperAdmin(user) || (user.ID != "" && league.OwnerID == user.ID)
+}
+
+// ownedLeague loads the league from the URL for its owner, answering the
+// request itself when that fails.
+func (s *Server) ownedLeague(w http.ResponseWriter, r *http.Request) (model.League, bool) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return model.League{}, false
+	}
+	if !canOwnLeague(league, s.currentUser(r)) {
+		http.Error(w, "tylko właściciel ligi może zmieniać jej ustawienia", http.StatusForbidden)
+		return model.League{}, false
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return model.League{}, false
+	}
+	return league, true
+}
+
+func (s *Server) handleLeagueEdit(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	form := LeagueSettingsForm{
+		Name:         league.Name,
+		Description:  league.Description,
+		Location:     league.Location,
+		ClubID:       league.ClubID,
+		StartDate:    league.StartDate.Format("2006-01-02"),
+		SetsPerMatch: league.SetsPerMatch,
+		PointsPerSet: setFormatForLeague(league).PointsToWin,
+	}
+	if league.EndDate != nil {
+		form.EndDate = league.EndDate.Format("2006-01-02")
+	}
+	s.renderLeagueEdit(w, r, league, form, nil)
+}
+
+func (s *Server) handleLeagueUpdate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	form := LeagueSettingsForm{
+		Name:        strings.TrimSpace(r.FormValue("name")),
+		Description: strings.TrimSpace(r.FormValue("description")),
+		Location:    strings.TrimSpace(r.FormValue("location")),
+		ClubID:      r.FormValue("club_id"),
+		StartDate:   strings.TrimSpace(r.FormValue("start_date")),
+		EndDate:     strings.TrimSpace(r.FormValue("end_date")),
+	}
+	form.SetsPerMatch, _ = strconv.Atoi(strings.TrimSpace(r.FormValue("sets_per_match")))
+	form.PointsPerSet, _ = strconv.Atoi(strings.TrimSpace(r.FormValue("points_per_set")))
+	updated, errors := s.applyLeagueSettings(league, form)
+	if len(errors) > 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		s.renderLeagueEdit(w, r, league, form, errors)
+		return
+	}
+	if err := s.store.UpdateLeague(updated); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=league_updated", http.StatusSeeOther)
+}
+
+// applyLeagueSettings validates the form against the league. The match
+// format is locked once the league has matches, since existing results were
+// scored under it.
+func (s *Server) applyLeagueSettings(league model.League, form LeagueSettingsForm) (model.League, []string) {
+	errors := []string{}
+	if form.Name == "" {
+		errors = append(errors, "Nazwa ligi jest wymagana.")
+	}
+	startDate, err := parseLeagueDate(form.StartDate)
+	if err != nil {
+		errors = append(errors, "Podaj prawidłową datę startu.")
+	}
+	endDate, err := parseOptionalLeagueDate(form.EndDate)
+	if err != nil {
+		errors = append(errors, "Podaj prawidłową datę końca.")
+	} else if endDate != nil && endDate.Before(startDate) {
+		errors = append(errors, "Data końca musi być po dacie startu.")
+	}
+	// Legacy leagues may keep an even length they already play with.
+	if form.SetsPerMatch != league.SetsPerMatch && !validSetsPerMatch(form.SetsPerMatch) {
+		errors = append(errors, "Liczba setów na mecz musi być nieparzysta: 1, 3, 5, 7 lub 9.")
+	}
+	if form.PointsPerSet != 11 && form.PointsPerSet != 15 {
+		errors = append(errors, "Sety mogą być rozgrywane do 11 lub 15 punktów.")
+	}
+	clubID, location, ok := s.clubLocation(form.ClubID, form.Location)
+	if !ok {
+		errors = append(errors, "Wybrany klub nie istnieje.")
+	}
+	if s.leagueFormatLocked(league) {
+		if form.SetsPerMatch != league.SetsPerMatch {
+			errors = append(errors, "Nie można zmienić liczby setów, bo w lidze są już mecze.")
+		}
+		if form.PointsPerSet != setFormatForLeague(league).PointsToWin {
+			errors = append(errors, "Nie można zmienić liczby punktów w secie, bo w lidze są już mecze.")
+		}
+	}
+	if len(errors) > 0 {
+		return league, errors
+	}
+	league.Name = form.Name
+	league.Description = form.Description
+	league.Location = location
+	league.ClubID = clubID
+	league.StartDate = startDate
+	league.EndDate = endDate
+	league.SetsPerMatch = form.SetsPerMatch
+	league.PointsPerSet = form.PointsPerSet
+	// A league ended by its admins stays finished whatever its new dates.
+	if league.Status != model.LeagueStatusFinished {
+		league.Status = model.LeagueStatusForDates(startDate, endDate, time.Now())
+	}
+	return league, nil
+}
+
+func (s *Server) leagueFormatLocked(league model.League) bool {
+	return len(s.store.ListMatches(league.ID)) > 0
+}
+
+func (s *Server) renderLeagueEdit(w http.ResponseWriter, r *http.Request, league model.League, form LeagueSettingsForm, errors []string) {
+	currentUser := s.currentUser(r)
+	view := LeagueEditView{
+		BaseView: BaseView{
+			Title:           "Ustawienia ligi",
+			CurrentUser:     currentUser,
+			Users:           s.store.ListUsers(),
+			IsAuthenticated: true,
+			IsDev:           isDevMode(),
+		},
+		League:       league,
+		Form:         form,
+		Errors:       errors,
+		FormatLocked: s.leagueFormatLocked(league),
+		IsOwner:      canOwnLeague(league, currentUser),
+		Clubs:        s.store.ListClubs(),
+	}
+	if err := s.templates.Render(w, "league_edit.html", view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleLeagueArchive is the owner's delete. Matches are kept so players can
+// still look back at their results.
+func (s *Server) handleLeagueArchive(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.ownedLeague(w, r)
+	if !ok {
+		return
+	}
+	if strings.TrimSpace(r.FormValue("confirm_name")) != league.Name {
+		http.Error(w, "aby usunąć ligę, przepisz dokładnie jej nazwę", http.StatusBadRequest)
+		return
+	}
+	now := time.Now()
+	league.ArchivedAt = &now
+	league.JoinCode = ""
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/?notice=league_archived", http.StatusSeeOther)
+}
+
+func (s *Server) handleLeagueRestore(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.ownedLeague(w, r)
+	if !ok {
+		return
+	}
+	league.ArchivedAt = nil
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=league_restored", http.StatusSeeOther)
+}
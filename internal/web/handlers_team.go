@@ -0,0 +1,635 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"sqoush-app/internal/model"
+)
+
+const (
+	minRubbers     = 3
+	maxRubbers     = 5
+	defaultRubbers = 3
+)
+
+func parseRubbersPerFixture(value string) int {
+	count, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || count < minRubbers || count > maxRubbers {
+		return defaultRubbers
+	}
+	return count
+}
+
+func parseCompetition(value string) model.CompetitionType {
+	if model.CompetitionType(strings.TrimSpace(value)) == model.CompetitionTeam {
+		return model.CompetitionTeam
+	}
+	return model.CompetitionIndividual
+}
+
+func (s *Server) handleTeamCreate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapManagePlayers)
+	if !ok {
+		return
+	}
+	if !league.IsTeamLeague() {
+		http.Error(w, "to nie jest liga drużynowa", http.StatusBadRequest)
+		return
+	}
+	name := strings.TrimSpace(r.FormValue("name"))
+	captainID := r.FormValue("captain_id")
+	if name == "" {
+		http.Error(w, "podaj nazwę drużyny", http.StatusBadRequest)
+		return
+	}
+	if !isLeaguePlayer(league, captainID) {
+		http.Error(w, "kapitanem może zostać tylko gracz ligi", http.StatusBadRequest)
+		return
+	}
+	if team, ok := teamOf(league, captainID); ok {
+		http.Error(w, fmt.Sprintf("kapitan gra już w drużynie %s", team.Name), http.StatusBadRequest)
+		return
+	}
+	league.Teams = append(league.Teams, model.Team{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Club:      strings.TrimSpace(r.FormValue("club")),
+		CaptainID: captainID,
+		PlayerIDs: []string{captainID},
+	})
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=team_created#teams", http.StatusSeeOther)
+}
+
+func (s *Server) handleTeamPlayerAdd(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	index := teamIndex(league, chi.URLParam(r, "teamID"))
+	if index < 0 {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !can(league, currentUser, model.CapManagePlayers) && league.Teams[index].CaptainID != currentUser.ID {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if league.IsArchived() {
+		http.Error(w, "liga jest zarchiwizowana", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	userID := r.FormValue("user_id")
+	if !isLeaguePlayer(league, userID) {
+		http.Error(w, "do drużyny można dodać tylko gracza ligi", http.StatusBadRequest)
+		return
+	}
+	if team, ok := teamOf(league, userID); ok {
+		http.Error(w, fmt.Sprintf("gracz jest już w drużynie %s", team.Name), http.StatusBadRequest)
+		return
+	}
+	league.Teams[index].PlayerIDs = append(league.Teams[index].PlayerIDs, userID)
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=team_player_added#teams", http.StatusSeeOther)
+}
+
+func (s *Server) handleFixtureCreate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	home, okHome := teamByID(league, r.FormValue("home_team_id"))
+	away, okAway := teamByID(league, r.FormValue("away_team_id"))
+	if !okHome || !okAway || home.ID == away.ID {
+		http.Error(w, "wybierz dwie różne drużyny", http.StatusBadRequest)
+		return
+	}
+	playAt, err := parsePlayedAt(r.FormValue("play_at"))
+	if err != nil {
+		http.Error(w, "nieprawidłowa data", http.StatusBadRequest)
+		return
+	}
+	fixture := model.Fixture{
+		ID:         uuid.NewString(),
+		LeagueID:   league.ID,
+		HomeTeamID: home.ID,
+		AwayTeamID: away.ID,
+		PlayAt:     playAt,
+		CreatedAt:  time.Now(),
+	}
+	if _, err := s.store.CreateFixture(fixture); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/fixtures/"+fixture.ID, http.StatusSeeOther)
+}
+
+func (s *Server) handleFixtureShow(w http.ResponseWriter, r *http.Request) {
+	fixture, league, ok := s.fixtureWithLeague(w, r)
+	if !ok {
+		return
+	}
+	view := s.fixturePageView(r, fixture, league)
+	if err := s.templates.Render(w, "fixture.html", view); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleFixtureOrder stores a captain's order of play. The order can change
+// until both captains have submitted theirs, which creates the rubbers.
+func (s *Server) handleFixtureOrder(w http.ResponseWriter, r *http.Request) {
+	fixture, league, ok := s.fixtureWithLeague(w, r)
+	if !ok {
+		return
+	}
+	currentUser := s.currentUser(r)
+	teamID := r.FormValue("team_id")
+	team, ok := teamByID(league, teamID)
+	if !ok || (teamID != fixture.HomeTeamID && teamID != fixture.AwayTeamID) {
+		http.Error(w, "nieprawidłowa drużyna", http.StatusBadRequest)
+		return
+	}
+	if team.CaptainID != currentUser.ID && !can(league, currentUser, model.CapManagePlayers) {
+		http.Error(w, "kolejność ustala kapitan drużyny", http.StatusForbidden)
+		return
+	}
+	if fixtureLocked(fixture, time.Now()) {
+		http.Error(w, "kolejność gry nie może być już zmieniona", http.StatusBadRequest)
+		return
+	}
+	order, errs := parseFixtureOrder(r, league, team, rubbersPerFixture(league))
+	if len(errs) > 0 {
+		view := s.fixturePageView(r, fixture, league)
+		view.OrderErrors = errs
+		w.WriteHeader(http.StatusBadRequest)
+		if err := s.templates.Render(w, "fixture.html", view); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	if teamID == fixture.HomeTeamID {
+		fixture.HomeOrder = order
+	} else {
+		fixture.AwayOrder = order
+	}
+	if err := s.store.UpdateFixture(fixture); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if len(fixture.HomeOrder) > 0 && len(fixture.AwayOrder) > 0 {
+		if err := s.createRubbers(fixture, league); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	http.Redirect(w, r, "/fixtures/"+fixture.ID+"?notice=order_saved", http.StatusSeeOther)
+}
+
+// handleRubberReport records the result of a scheduled rubber. From there the
+// rubber follows the regular confirmation flow of league matches.
+func (s *Server) handleRubberReport(w http.ResponseWriter, r *http.Request) {
+	fixture, league, ok := s.fixtureWithLeague(w, r)
+	if !ok {
+		return
+	}
+	match, ok := s.store.GetMatch(chi.URLParam(r, "matchID"))
+	if !ok || match.FixtureID != fixture.ID {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !canReportRubber(league, fixture, match, currentUser) {
+		http.Error(w, "brak uprawnień", http.StatusForbidden)
+		return
+	}
+	if match.Status != model.MatchScheduled {
+		http.Error(w, "wynik tego meczu został już zgłoszony", http.StatusBadRequest)
+		return
+	}
+	outcome, conceded := parseMatchOutcome(r)
+	sets, errs := parseMatchResult(r, matchSetFormat(league, match), outcome, conceded)
+	if len(errs) > 0 {
+		view := s.fixturePageView(r, fixture, league)
+		view.RubberErrors = errs
+		view.RubberErrorID = match.ID
+		w.WriteHeader(http.StatusBadRequest)
+		if err := s.templates.Render(w, "fixture.html", view); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	match.Sets = sets
+	match.Outcome = outcome
+	match.ConcededBy = sidePlayerID(conceded, match.PlayerAID, match.PlayerBID)
+	match.Status = model.MatchPending
+	match.ReportedBy = currentUser.ID
+	reportedAt := time.Now()
+	match.ReportedAt = &reportedAt
+	if err := s.store.UpdateMatch(match); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/fixtures/"+fixture.ID+"?notice=rubber_reported", http.StatusSeeOther)
+}
+
+func (s *Server) fixtureWithLeague(w http.ResponseWriter, r *http.Request) (model.Fixture, model.League, bool) {
+	fixture, ok := s.store.GetFixture(chi.URLParam(r, "fixtureID"))
+	if !ok {
+		http.NotFound(w, r)
+		return model.Fixture{}, model.League{}, false
+	}
+	league, ok := s.store.GetLeague(fixture.LeagueID)
+	if !ok || !canViewLeague(league, s.currentUser(r)) {
+		http.NotFound(w, r)
+		return model.Fixture{}, model.League{}, false
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return model.Fixture{}, model.League{}, false
+	}
+	return fixture, league, true
+}
+
+func (s *Server) createRubbers(fixture model.Fixture, league model.League) error {
+	if len(s.fixtureRubbers(fixture)) > 0 {
+		return nil
+	}
+	now := time.Now()
+	for i := range min(len(fixture.HomeOrder), len(fixture.AwayOrder)) {
+		rubber := model.Match{
+			ID:        uuid.NewString(),
+			LeagueID:  fixture.LeagueID,
+			PlayerAID: fixture.HomeOrder[i],
+			PlayerBID: fixture.AwayOrder[i],
+			FixtureID: fixture.ID,
+			Rubber:    i + 1,
+			Status:    model.MatchScheduled,
+			CreatedAt: now,
+		}
+		rubber.HeadStartA, rubber.HeadStartB = s.headStarts(league, rubber.SideA(), rubber.SideB())
+		if _, err := s.store.CreateMatch(rubber); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) fixtureRubbers(fixture model.Fixture) []model.Match {
+	rubbers := []model.Match{}
+	for _, match := range s.store.ListMatches(fixture.LeagueID) {
+		if match.FixtureID == fixture.ID {
+			rubbers = append(rubbers, match)
+		}
+	}
+	sort.Slice(rubbers, func(i, j int) bool { return rubbers[i].Rubber < rubbers[j].Rubber })
+	return rubbers
+}
+
+func (s *Server) fixturePageView(r *http.Request, fixture model.Fixture, league model.League) FixturePageView {
+	currentUser := s.currentUser(r)
+	home, _ := teamByID(league, fixture.HomeTeamID)
+	away, _ := teamByID(league, fixture.AwayTeamID)
+	rubbers := s.fixtureRubbers(fixture)
+	canManage := can(league, currentUser, model.CapEditResults)
+	locked := fixtureLocked(fixture, time.Now())
+	view := FixturePageView{
+		BaseView: BaseView{
+			Title:           fmt.Sprintf("%s – %s", home.Name, away.Name),
+			CurrentUser:     currentUser,
+			Users:           s.store.ListUsers(),
+			IsAuthenticated: true,
+			IsDev:           isDevMode(),
+			FlashSuccess:    flashMessage(r.URL.Query().Get("notice")),
+		},
+		League:  league,
+		Fixture: s.fixtureView(fixture, league, rubbers),
+		Locked:  locked,
+	}
+	// Until the rubbers exist a captain only sees their own order of play.
+	ordersVisible := len(rubbers) > 0 || canManage
+	for _, side := range []struct {
+		label string
+		team  model.Team
+		order []string
+	}{
+		{"Gospodarze", home, fixture.HomeOrder},
+		{"Goście", away, fixture.AwayOrder},
+	} {
+		isCaptain := side.team.CaptainID == currentUser.ID
+		sideView := FixtureSideView{
+			Label:     side.label,
+			Team:      side.team,
+			Players:   s.usersByID(side.team.PlayerIDs),
+			Submitted: len(side.order) > 0,
+			CanOrder:  !locked && (canManage || isCaptain),
+		}
+		if ordersVisible || isCaptain {
+			sideView.Order = s.usersByID(side.order)
+		}
+		for i := range rubbersPerFixture(league) {
+			slot := FixtureSlotView{Number: i + 1}
+			if i < len(sideView.Order) {
+				slot.SelectedID = sideView.Order[i].ID
+			}
+			sideView.Slots = append(sideView.Slots, slot)
+		}
+		view.Sides = append(view.Sides, sideView)
+	}
+	format := setFormatForLeague(league)
+	for _, match := range rubbers {
+		matchView := s.matchView(match, currentUser)
+		view.Rubbers = append(view.Rubbers, RubberView{
+			Number:    match.Rubber,
+			Match:     matchView,
+			CanReport: match.Status == model.MatchScheduled && canReportRubber(league, fixture, match, currentUser),
+			Fields: MatchResultFieldsView{
+				PlayerA: matchView.PlayerA,
+				PlayerB: matchView.PlayerB,
+				Outcome: model.OutcomeNormal,
+				Sets:    matchSetInputs(nil, format.BestOf),
+			},
+		})
+	}
+	return view
+}
+
+func (s *Server) fixtureView(fixture model.Fixture, league model.League, rubbers []model.Match) FixtureView {
+	home, _ := teamByID(league, fixture.HomeTeamID)
+	away, _ := teamByID(league, fixture.AwayTeamID)
+	result := computeFixtureResult(rubbers, rubbersPerFixture(league), setFormatForLeague(league), scoringForLeague(league))
+	status := "Oczekuje na kolejność gry"
+	switch {
+	case result.Complete:
+		status = "Zakończony"
+	case len(rubbers) > 0:
+		status = fmt.Sprintf("W trakcie (%d/%d)", result.Confirmed, len(rubbers))
+	case len(fixture.HomeOrder) > 0 || len(fixture.AwayOrder) > 0:
+		status = "Czeka na drugą kolejność gry"
+	}
+	return FixtureView{
+		Fixture:     fixture,
+		Home:        home,
+		Away:        away,
+		PlayAtLabel: fixture.PlayAt.Format("02 Jan 2006 15:04"),
+		Result:      result,
+		StatusText:  status,
+	}
+}
+
+func (s *Server) leagueFixtureViews(league model.League, matches []model.Match) []FixtureView {
+	byFixture := map[string][]model.Match{}
+	for _, match := range matches {
+		if match.FixtureID != "" {
+			byFixture[match.FixtureID] = append(byFixture[match.FixtureID], match)
+		}
+	}
+	views := []FixtureView{}
+	for _, fixture := range s.store.ListFixtures(league.ID) {
+		views = append(views, s.fixtureView(fixture, league, byFixture[fixture.ID]))
+	}
+	return views
+}
+
+func (s *Server) teamViews(league model.League, currentUser model.User) []TeamView {
+	canManage := can(league, currentUser, model.CapManagePlayers)
+	views := make([]TeamView, 0, len(league.Teams))
+	for _, team := range league.Teams {
+		captain, _ := s.store.GetUser(team.CaptainID)
+		views = append(views, TeamView{
+			Team:      team,
+			Captain:   captain,
+			Players:   s.usersByID(team.PlayerIDs),
+			CanManage: canManage || team.CaptainID == currentUser.ID,
+		})
+	}
+	return views
+}
+
+// teamCandidates lists users who do not play for any team yet.
+// teamCandidates lists league players without a team. Players join the
+// league first, so its cap and approval rules apply to team leagues too.
+func (s *Server) teamCandidates(league model.League) []model.User {
+	candidates := []model.User{}
+	for _, user := range s.leaguePlayers(league) {
+		if _, ok := teamOf(league, user.ID); !ok {
+			candidates = append(candidates, user)
+		}
+	}
+	return candidates
+}
+
+type fixtureResult struct {
+	HomeRubbers int
+	AwayRubbers int
+	Confirmed   int
+	Complete    bool
+	Winner      string
+}
+
+// computeFixtureResult adds up confirmed rubbers. A fixture counts once every
+// rubber of the order of play is confirmed or voided.
+func computeFixtureResult(rubbers []model.Match, expected int, format SetFormat, scoring model.ScoringRules) fixtureResult {
+	result := fixtureResult{}
+	played := 0
+	for _, rubber := range rubbers {
+		if rubber.Status != model.MatchVoided {
+			played++
+		}
+		if rubber.Status != model.MatchConfirmed {
+			continue
+		}
+		result.Confirmed++
+		switch leagueMatchResult(rubber, format, scoring).Winner {
+		case sideA:
+			result.HomeRubbers++
+		case sideB:
+			result.AwayRubbers++
+		}
+	}
+	result.Complete = played > 0 && result.Confirmed == played && len(rubbers) >= expected
+	if result.Complete {
+		switch {
+		case result.HomeRubbers > result.AwayRubbers:
+			result.Winner = sideA
+		case result.AwayRubbers > result.HomeRubbers:
+			result.Winner = sideB
+		}
+	}
+	return result
+}
+
+// BuildTeamStandings ranks teams on completed fixtures. A win and a loss score
+// like a league match; a drawn fixture gives both teams the mean of the two,
+// rounded down. Ties are split on rubber difference, then rubbers won.
+func BuildTeamStandings(teams []model.Team, fixtures []FixtureView, scoring model.ScoringRules) []TeamStandingEntry {
+	index := map[string]*TeamStandingEntry{}
+	for _, team := range teams {
+		index[team.ID] = &TeamStandingEntry{Team: team}
+	}
+	draw := (scoring.Win + scoring.Loss) / 2
+	for _, fixture := range fixtures {
+		if !fixture.Result.Complete {
+			continue
+		}
+		home, away := index[fixture.Home.ID], index[fixture.Away.ID]
+		if home == nil || away == nil {
+			continue
+		}
+		home.addFixture(fixture.Result.HomeRubbers, fixture.Result.AwayRubbers)
+		away.addFixture(fixture.Result.AwayRubbers, fixture.Result.HomeRubbers)
+		switch fixture.Result.Winner {
+		case sideA:
+			home.Wins++
+			home.Points += scoring.Win
+			away.Losses++
+			away.Points += scoring.Loss
+		case sideB:
+			away.Wins++
+			away.Points += scoring.Win
+			home.Losses++
+			home.Points += scoring.Loss
+		default:
+			home.Draws++
+			away.Draws++
+			home.Points += draw
+			away.Points += draw
+		}
+	}
+	entries := make([]TeamStandingEntry, 0, len(index))
+	for _, team := range teams {
+		entries = append(entries, *index[team.ID])
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Points != b.Points {
+			return a.Points > b.Points
+		}
+		if a.RubbersWon-a.RubbersLost != b.RubbersWon-b.RubbersLost {
+			return a.RubbersWon-a.RubbersLost > b.RubbersWon-b.RubbersLost
+		}
+		if a.RubbersWon != b.RubbersWon {
+			return a.RubbersWon > b.RubbersWon
+		}
+		return a.Team.Name < b.Team.Name
+	})
+	for i := range entries {
+		entries[i].Position = i + 1
+	}
+	return entries
+}
+
+func (e *TeamStandingEntry) addFixture(won, lost int) {
+	e.Fixtures++
+	e.RubbersWon += won
+	e.RubbersLost += lost
+}
+
+func parseFixtureOrder(r *http.Request, league model.League, team model.Team, slots int) ([]string, []string) {
+	order := make([]string, 0, slots)
+	errs := []string{}
+	seen := map[string]bool{}
+	for i := 1; i <= slots; i++ {
+		id := r.FormValue(fmt.Sprintf("rubber_%d", i))
+		switch {
+		case id == "":
+			errs = append(errs, fmt.Sprintf("Wybierz gracza na pozycję %d.", i))
+		case !slices.Contains(team.PlayerIDs, id):
+			errs = append(errs, fmt.Sprintf("Gracz na pozycji %d nie należy do drużyny.", i))
+		case league.IsSuspended(id):
+			errs = append(errs, fmt.Sprintf("Gracz na pozycji %d jest zawieszony.", i))
+		case seen[id]:
+			errs = append(errs, fmt.Sprintf("Gracz na pozycji %d występuje już wyżej w kolejności.", i))
+		}
+		seen[id] = true
+		order = append(order, id)
+	}
+	return order, errs
+}
+
+func canReportRubber(league model.League, fixture model.Fixture, match model.Match, user model.User) bool {
+	if user.ID == "" {
+		return false
+	}
+	if match.Involves(user.ID) || can(league, user, model.CapEditResults) {
+		return true
+	}
+	home, _ := teamByID(league, fixture.HomeTeamID)
+	away, _ := teamByID(league, fixture.AwayTeamID)
+	return user.ID == home.CaptainID || user.ID == away.CaptainID
+}
+
+// fixtureLocked reports whether the order of play is final: both captains
+// submitted theirs or the fixture has started.
+func fixtureLocked(fixture model.Fixture, now time.Time) bool {
+	return (len(fixture.HomeOrder) > 0 && len(fixture.AwayOrder) > 0) || !now.Before(fixture.PlayAt)
+}
+
+func rubbersPerFixture(league model.League) int {
+	if league.RubbersPerFixture < minRubbers || league.RubbersPerFixture > maxRubbers {
+		return defaultRubbers
+	}
+	return league.RubbersPerFixture
+}
+
+func teamByID(league model.League, teamID string) (model.Team, bool) {
+	if index := teamIndex(league, teamID); index >= 0 {
+		return league.Teams[index], true
+	}
+	return model.Team{}, false
+}
+
+func teamIndex(league model.League, teamID string) int {
+	for i, team := range league.Teams {
+		if team.ID == teamID {
+			return i
+		}
+	}
+	return -1
+}
+
+func teamOf(league model.League, userID string) (model.Team, bool) {
+	for _, team := range league.Teams {
+		if slices.Contains(team.PlayerIDs, userID) {
+			return team, true
+		}
+	}
+	return model.Team{}, false
+}
+
+// nextSeasonTeams carries the teams over to a new season, keeping only the
+// players who continue in the league.
+func nextSeasonTeams(teams []model.Team, playerIDs []string) []model.Team {
+	next := make([]model.Team, 0, len(teams))
+	for _, team := range teams {
+		players := []string{}
+		for _, id := range team.PlayerIDs {
+			if slices.Contains(playerIDs, id) {
+				players = append(players, id)
+			}
+		}
+		team.PlayerIDs = players
+		next = append(next, team)
+	}
+	return next
+}
@@ -0,0 +1,174 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/waitlist"
+)
+
+// parseMaxPlayers reads the optional roster cap; anything below two players
+// means no limit.
+func parseMaxPlayers(value string) int {
+	if players, _ := strconv.Atoi(strings.TrimSpace(value)); players >= 2 {
+		return players
+	}
+	return 0
+}
+
+func (s *Server) handleCapacityUpdate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapEditSettings)
+	if !ok {
+		return
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(r.FormValue("max_players")))
+	if err != nil || value < 0 || value == 1 {
+		http.Error(w, "nieprawidłowy limit graczy", http.StatusBadRequest)
+		return
+	}
+	if value > 0 && value < len(league.PlayerIDs) {
+		http.Error(w, fmt.Sprintf("w lidze gra już %d graczy, limit nie może być mniejszy", len(league.PlayerIDs)), http.StatusBadRequest)
+		return
+	}
+	league.MaxPlayers = value
+	if value == 0 {
+		// Without a cap everyone waiting can simply join.
+		for _, entry := range league.Waitlist {
+			if !isLeaguePlayer(league, entry.UserID) {
+				league.PlayerIDs = append(league.PlayerIDs, entry.UserID)
+			}
+			if err := s.decideJoinRequest(league, entry.UserID, s.currentUser(r).ID, model.JoinRequestApproved); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		league.Waitlist = nil
+	}
+	waitlist.Refresh(&league, time.Now())
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=capacity_saved#waitlist", http.StatusSeeOther)
+}
+
+// handleWaitlistAccept takes the slot held for the current user.
+func (s *Server) handleWaitlistAccept(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if league.IsArchived() {
+		http.Error(w, "liga jest zarchiwizowana", http.StatusBadRequest)
+		return
+	}
+	currentUser := s.currentUser(r)
+	entry, _, onList := league.WaitlistEntry(currentUser.ID)
+	if !onList || !entry.Offered() || !time.Now().Before(*entry.OfferExpiresAt) {
+		http.Error(w, "nie masz aktualnej propozycji miejsca w tej lidze", http.StatusBadRequest)
+		return
+	}
+	waitlist.Remove(&league, currentUser.ID)
+	league.PlayerIDs = append(league.PlayerIDs, currentUser.ID)
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if err := s.decideJoinRequest(league, currentUser.ID, "", model.JoinRequestApproved); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=league_joined", http.StatusSeeOther)
+}
+
+// handleWaitlistLeave takes the current user off the waitlist. A held slot
+// goes straight to the next player in line.
+func (s *Server) handleWaitlistLeave(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if err := s.leaveWaitlist(league, s.currentUser(r).ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=waitlist_left", http.StatusSeeOther)
+}
+
+func (s *Server) handleWaitlistRemove(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapManagePlayers)
+	if !ok {
+		return
+	}
+	if err := s.leaveWaitlist(league, chi.URLParam(r, "userID")); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=waitlist_removed#waitlist", http.StatusSeeOther)
+}
+
+func (s *Server) leaveWaitlist(league model.League, userID string) error {
+	if _, _, ok := league.WaitlistEntry(userID); !ok {
+		return nil
+	}
+	waitlist.Remove(&league, userID)
+	waitlist.Refresh(&league, time.Now())
+	return s.store.UpdateLeague(league)
+}
+
+// decideJoinRequest settles the user's open join request with status, or
+// records one when the user joined without asking.
+func (s *Server) decideJoinRequest(league model.League, userID, decidedBy string, status model.JoinRequestStatus) error {
+	now := time.Now()
+	for _, req := range s.store.ListJoinRequests(league.ID) {
+		if req.UserID != userID || (req.Status != model.JoinRequestPending && req.Status != model.JoinRequestWaitlisted) {
+			continue
+		}
+		req.Status = status
+		req.DecidedBy = decidedBy
+		req.DecidedAt = &now
+		return s.store.UpdateJoinRequest(req)
+	}
+	_, err := s.store.CreateJoinRequest(model.LeagueJoinRequest{
+		LeagueID:  league.ID,
+		UserID:    userID,
+		Status:    status,
+		CreatedAt: now,
+		DecidedBy: decidedBy,
+		DecidedAt: &now,
+	})
+	return err
+}
+
+func capacityLabel(league model.League) string {
+	if league.MaxPlayers <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d graczy", len(league.PlayerIDs), league.MaxPlayers)
+}
+
+func (s *Server) waitlistViews(league model.League) []WaitlistView {
+	now := time.Now()
+	views := []WaitlistView{}
+	for i, entry := range league.Waitlist {
+		player, _ := s.store.GetUser(entry.UserID)
+		view := WaitlistView{
+			Player:   player,
+			Position: i + 1,
+			Offered:  entry.Offered() && now.Before(*entry.OfferExpiresAt),
+		}
+		if view.Offered {
+			view.DeadlineLabel = entry.OfferExpiresAt.Format("02 Jan 2006 15:04")
+		}
+		views = append(views, view)
+	}
+	return views
+}
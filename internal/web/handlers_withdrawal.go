@@ -0,0 +1,290 @@
+package web
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/waitlist"
+)
+
+func parseWithdrawalPolicy(value string, fallback model.WithdrawalPolicy) model.WithdrawalPolicy {
+	switch policy := model.WithdrawalPolicy(strings.TrimSpace(value)); policy {
+	case model.WithdrawalKeep, model.WithdrawalVoidAll, model.WithdrawalVoidUnplayed:
+		return policy
+	}
+	if fallback == "" {
+		return model.WithdrawalKeep
+	}
+	return fallback
+}
+
+func withdrawalPolicyLabel(policy model.WithdrawalPolicy) string {
+	switch policy {
+	case model.WithdrawalVoidAll:
+		return "wszystkie mecze anulowane"
+	case model.WithdrawalVoidUnplayed:
+		return "nierozegrane mecze anulowane"
+	}
+	return "wyniki zachowane"
+}
+
+func withdrawalKindLabel(kind model.WithdrawalKind) string {
+	switch kind {
+	case model.WithdrawalRemoved:
+		return "Usunięty przez administratora"
+	case model.WithdrawalSuspended:
+		return "Zawieszony"
+	}
+	return "Wycofał(a) się"
+}
+
+func (s *Server) handlePlayerRemove(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapManagePlayers)
+	if !ok {
+		return
+	}
+	userID := chi.URLParam(r, "userID")
+	if !isLeaguePlayer(league, userID) {
+		http.Error(w, "gracz nie należy do ligi", http.StatusBadRequest)
+		return
+	}
+	if userID == league.OwnerID {
+		http.Error(w, "nie można usunąć właściciela ligi", http.StatusBadRequest)
+		return
+	}
+	policy := parseWithdrawalPolicy(r.FormValue("policy"), league.WithdrawalPolicy)
+	withdrawal := model.Withdrawal{
+		UserID: userID,
+		Kind:   model.WithdrawalRemoved,
+		Policy: policy,
+		Reason: strings.TrimSpace(r.FormValue("reason")),
+		ByID:   s.currentUser(r).ID,
+	}
+	if err := s.withdrawPlayer(league, withdrawal); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=player_removed#roster", http.StatusSeeOther)
+}
+
+func (s *Server) handleLeagueWithdraw(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.store.GetLeague(chi.URLParam(r, "leagueID"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	currentUser := s.currentUser(r)
+	if !isLeaguePlayer(league, currentUser.ID) {
+		http.Error(w, "nie grasz w tej lidze", http.StatusBadRequest)
+		return
+	}
+	if currentUser.ID == league.OwnerID {
+		http.Error(w, "właściciel ligi nie może się z niej wycofać", http.StatusBadRequest)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "nieprawidłowe dane", http.StatusBadRequest)
+		return
+	}
+	withdrawal := model.Withdrawal{
+		UserID: currentUser.ID,
+		Kind:   model.WithdrawalWithdrawn,
+		Policy: parseWithdrawalPolicy("", league.WithdrawalPolicy),
+		Reason: strings.TrimSpace(r.FormValue("reason")),
+		ByID:   currentUser.ID,
+	}
+	if err := s.withdrawPlayer(league, withdrawal); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=player_withdrawn", http.StatusSeeOther)
+}
+
+func (s *Server) handlePlayerSuspend(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapManagePlayers)
+	if !ok {
+		return
+	}
+	userID := chi.URLParam(r, "userID")
+	if !isLeaguePlayer(league, userID) {
+		http.Error(w, "gracz nie należy do ligi", http.StatusBadRequest)
+		return
+	}
+	if !league.IsSuspended(userID) {
+		league.Withdrawals = append(league.Withdrawals, model.Withdrawal{
+			UserID:    userID,
+			Kind:      model.WithdrawalSuspended,
+			Reason:    strings.TrimSpace(r.FormValue("reason")),
+			ByID:      s.currentUser(r).ID,
+			CreatedAt: time.Now(),
+		})
+	}
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=player_suspended#roster", http.StatusSeeOther)
+}
+
+func (s *Server) handlePlayerReinstate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.manageableLeague(w, r, model.CapManagePlayers)
+	if !ok {
+		return
+	}
+	league.Withdrawals = withoutSuspension(league.Withdrawals, chi.URLParam(r, "userID"))
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=player_reinstated#roster", http.StatusSeeOther)
+}
+
+// withdrawPlayer takes the player off the roster, records why and applies the
+// withdrawal policy to their matches.
+func (s *Server) withdrawPlayer(league model.League, withdrawal model.Withdrawal) error {
+	if err := s.store.RemovePlayerFromLeague(league.ID, withdrawal.UserID); err != nil {
+		return err
+	}
+	updated, ok := s.store.GetLeague(league.ID)
+	if !ok {
+		return nil
+	}
+	withdrawal.CreatedAt = time.Now()
+	updated.Withdrawals = append(withoutSuspension(updated.Withdrawals, withdrawal.UserID), withdrawal)
+	waitlist.Refresh(&updated, withdrawal.CreatedAt)
+	if err := s.store.UpdateLeague(updated); err != nil {
+		return err
+	}
+	reason := "Gracz opuścił ligę"
+	if withdrawal.Reason != "" {
+		reason += ": " + withdrawal.Reason
+	}
+	for _, match := range s.store.ListMatches(league.ID) {
+		if !match.Involves(withdrawal.UserID) || !voidedByPolicy(match, withdrawal.Policy) {
+			continue
+		}
+		if err := s.ensureReportedRevision(match); err != nil {
+			return err
+		}
+		match.Status = model.MatchVoided
+		match.AutoConfirmed = false
+		if err := s.store.UpdateMatch(match); err != nil {
+			return err
+		}
+		if _, err := s.store.CreateMatchRevision(matchRevisionFrom(match, model.RevisionVoided, withdrawal.ByID, reason)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// voidedByPolicy reports whether the match is cancelled when one of its
+// players leaves. Matches without a confirmed result count as unplayed.
+func voidedByPolicy(match model.Match, policy model.WithdrawalPolicy) bool {
+	switch policy {
+	case model.WithdrawalVoidAll:
+		return match.Status != model.MatchVoided && match.Status != model.MatchRejected
+	case model.WithdrawalVoidUnplayed:
+		return match.Status == model.MatchScheduled || match.Status == model.MatchPending || match.Status == model.MatchDisputed
+	}
+	return false
+}
+
+// tablePlayers narrows ids to the players who belong in the standings:
+// current players and those who left without their results being voided.
+func (s *Server) tablePlayers(league model.League, ids []string) []model.User {
+	kept := []string{}
+	for _, id := range ids {
+		if isLeaguePlayer(league, id) {
+			kept = append(kept, id)
+			continue
+		}
+		if withdrawal, ok := leftLeague(league, id); ok && withdrawal.Policy != model.WithdrawalVoidAll {
+			kept = append(kept, id)
+		}
+	}
+	return s.usersByID(kept)
+}
+
+// activePlayers narrows ids to the players who can play new matches.
+func (s *Server) activePlayers(league model.League, ids []string) []model.User {
+	active := []string{}
+	for _, id := range ids {
+		if isLeaguePlayer(league, id) && !league.IsSuspended(id) {
+			active = append(active, id)
+		}
+	}
+	return s.usersByID(active)
+}
+
+func markWithdrawn(league model.League, entries []StandingEntry) []StandingEntry {
+	for i := range entries {
+		if _, ok := leftLeague(league, entries[i].Player.ID); ok {
+			entries[i].Withdrawn = true
+		}
+	}
+	return entries
+}
+
+// leftLeague returns the latest withdrawal of a player who is no longer in
+// the league.
+func leftLeague(league model.League, userID string) (model.Withdrawal, bool) {
+	if isLeaguePlayer(league, userID) {
+		return model.Withdrawal{}, false
+	}
+	for _, withdrawal := range slices.Backward(league.Withdrawals) {
+		if withdrawal.UserID == userID && withdrawal.Kind != model.WithdrawalSuspended {
+			return withdrawal, true
+		}
+	}
+	return model.Withdrawal{}, false
+}
+
+func withoutSuspension(withdrawals []model.Withdrawal, userID string) []model.Withdrawal {
+	kept := []model.Withdrawal{}
+	for _, withdrawal := range withdrawals {
+		if withdrawal.UserID == userID && withdrawal.Kind == model.WithdrawalSuspended {
+			continue
+		}
+		kept = append(kept, withdrawal)
+	}
+	return kept
+}
+
+func (s *Server) rosterViews(league model.League) []RosterView {
+	views := []RosterView{}
+	for _, player := range s.leaguePlayers(league) {
+		views = append(views, RosterView{
+			Player:    player,
+			Suspended: league.IsSuspended(player.ID),
+			IsOwner:   player.ID == league.OwnerID,
+		})
+	}
+	return views
+}
+
+func (s *Server) withdrawalViews(league model.League) []WithdrawalView {
+	views := []WithdrawalView{}
+	for _, withdrawal := range league.Withdrawals {
+		if isLeaguePlayer(league, withdrawal.UserID) && withdrawal.Kind != model.WithdrawalSuspended {
+			continue
+		}
+		player, _ := s.store.GetUser(withdrawal.UserID)
+		view := WithdrawalView{
+			Player:    player,
+			KindText:  withdrawalKindLabel(withdrawal.Kind),
+			Reason:    withdrawal.Reason,
+			DateLabel: withdrawal.CreatedAt.Format("02 Jan 2006"),
+		}
+		if withdrawal.Kind != model.WithdrawalSuspended {
+			view.PolicyText = withdrawalPolicyLabel(withdrawal.Policy)
+		}
+		views = append(views, view)
+	}
+	return views
+}
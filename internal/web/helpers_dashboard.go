@@ -17,7 +17,7 @@ func (s *Server) leagueActivityEntries(currentUser model.User, statuses map[mode
 	for _, league := range s.leaguesForUser(currentUser.ID) {
 		matches := s.store.ListMatches(league.ID)
 		for _, match := range matches {
-			if match.PlayerAID != currentUser.ID && match.PlayerBID != currentUser.ID {
+			if !match.Involves(currentUser.ID) {
 				continue
 			}
 			if !statuses[match.Status] {
@@ -33,11 +33,11 @@ func (s *Server) leagueActivityEntries(currentUser model.User, statuses map[mode
 				StatusText:    view.StatusText,
 				CanConfirm:    view.CanConfirm,
 				CanReject:     view.CanReject,
-				PlayedAtLabel: match.CreatedAt.Format("02 Jan 2006 15:04"),
+				PlayedAtLabel: match.ReportTime().Format("02 Jan 2006 15:04"),
 				LeagueName:    league.Name,
 			}
 			entries = append(entries, activityEntry{
-				When: match.CreatedAt,
+				When: match.ReportTime(),
 				Item: item,
 			})
 		}
@@ -48,7 +48,7 @@ func (s *Server) leagueActivityEntries(currentUser model.User, statuses map[mode
 func (s *Server) friendlyActivityEntries(currentUser model.User, statuses map[model.MatchStatus]bool) []activityEntry {
 	entries := []activityEntry{}
 	for _, match := range s.store.ListFriendlyMatches() {
-		if match.PlayerAID != currentUser.ID && match.PlayerBID != currentUser.ID {
+		if !match.Involves(currentUser.ID) {
 			continue
 		}
 		if !statuses[match.Status] {
@@ -4,29 +4,57 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"sqoush-app/internal/model"
 )
 
-func parseSets(r *http.Request, maxSets int) []model.SetScore {
+func parseSets(r *http.Request, maxSets int) ([]model.SetScore, []SetError) {
 	sets := []model.SetScore{}
+	errs := []SetError{}
 	if maxSets < 1 {
 		maxSets = 5
 	}
+	emptySet := 0
 	for i := 1; i <= maxSets; i++ {
-		aStr := r.FormValue(fmt.Sprintf("set_%d_a", i))
-		bStr := r.FormValue(fmt.Sprintf("set_%d_b", i))
+		aStr := strings.TrimSpace(r.FormValue(fmt.Sprintf("set_%d_a", i)))
+		bStr := strings.TrimSpace(r.FormValue(fmt.Sprintf("set_%d_b", i)))
+		if aStr == "" && bStr == "" {
+			if emptySet == 0 {
+				emptySet = i
+			}
+			continue
+		}
+		if emptySet > 0 {
+			errs = append(errs, SetError{Set: emptySet, Message: "brak wyniku, a kolejne sety są uzupełnione"})
+			emptySet = 0
+		}
 		if aStr == "" || bStr == "" {
+			errs = append(errs, SetError{Set: i, Message: "uzupełnij wynik obu graczy"})
 			continue
 		}
 		a, errA := strconv.Atoi(aStr)
 		b, errB := strconv.Atoi(bStr)
 		if errA != nil || errB != nil {
+			errs = append(errs, SetError{Set: i, Message: "wynik musi być liczbą całkowitą"})
 			continue
 		}
 		sets = append(sets, model.SetScore{A: a, B: b})
 	}
-	return sets
+	return sets, errs
+}
+
+func parseSetFormat(r *http.Request) SetFormat {
+	bestOf, _ := strconv.Atoi(strings.TrimSpace(r.FormValue("best_of")))
+	pointsToWin, _ := strconv.Atoi(strings.TrimSpace(r.FormValue("points_per_set")))
+	return SetFormat{BestOf: bestOf, PointsToWin: pointsToWin}.normalized()
+}
+
+func parsePointsPerSet(value string) int {
+	if strings.TrimSpace(value) == "15" {
+		return 15
+	}
+	return defaultPointsPerSet
 }
 
 func buildSetsRange(maxSets int) []int {
@@ -40,18 +68,24 @@ func buildSetsRange(maxSets int) []int {
 	return sets
 }
 
-func parseSetsPerMatch(value string) int {
+// parseSetsPerMatch reads the best-of length of league matches. An empty
+// value means five sets; anything but an odd number from 1 to 9 is rejected.
+func parseSetsPerMatch(value string) (int, bool) {
+	value = strings.TrimSpace(value)
 	if value == "" {
-		return 5
+		return 5, true
 	}
 	parsed, err := strconv.Atoi(value)
-	if err != nil || parsed < 1 {
-		return 5
+	if err != nil || !validSetsPerMatch(parsed) {
+		return 0, false
 	}
-	if parsed > 9 {
-		return 9
-	}
-	return parsed
+	return parsed, true
+}
+
+// validSetsPerMatch reports whether matches of that many sets always have a
+// winner: an odd number from 1 to 9.
+func validSetsPerMatch(sets int) bool {
+	return sets >= 1 && sets <= 9 && sets%2 == 1
 }
 
 func parseSetsCount(value string, max int) int {
@@ -67,3 +101,38 @@ func parseSetsCount(value string, max int) int {
 	}
 	return parsed
 }
+
+func parseConfirmationHours(value string) int {
+	hours, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 72
+	}
+	switch hours {
+	case 0, 24, 48, 72, 168:
+		return hours
+	}
+	return 72
+}
+
+func parseAutoConfirmAction(value string) model.AutoConfirmAction {
+	if model.AutoConfirmAction(strings.TrimSpace(value)) == model.AutoConfirmEscalate {
+		return model.AutoConfirmEscalate
+	}
+	return model.AutoConfirmConfirm
+}
+
+func parseScoringRules(r *http.Request) model.ScoringRules {
+	return model.ScoringRules{
+		Win:     parseScoringPoints(r.FormValue("points_win"), defaultScoring.Win),
+		Loss:    parseScoringPoints(r.FormValue("points_loss"), defaultScoring.Loss),
+		Forfeit: parseScoringPoints(r.FormValue("points_forfeit"), defaultScoring.Forfeit),
+	}
+}
+
+func parseScoringPoints(value string, fallback int) int {
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || parsed < 0 || parsed > 10 {
+		return fallback
+	}
+	return parsed
+}
@@ -0,0 +1,184 @@
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"sqoush-app/internal/model"
+)
+
+const (
+	sideA = "a"
+	sideB = "b"
+)
+
+var defaultScoring = model.ScoringRules{Win: 3, Loss: 1, Forfeit: 0}
+
+type matchResult struct {
+	Winner        string
+	SetsA         int
+	SetsB         int
+	PointsA       int
+	PointsB       int
+	LeaguePointsA int
+	LeaguePointsB int
+}
+
+func scoringForLeague(league model.League) model.ScoringRules {
+	if league.Scoring.IsZero() {
+		return defaultScoring
+	}
+	return league.Scoring
+}
+
+func normalizeOutcome(outcome model.MatchOutcome) model.MatchOutcome {
+	switch outcome {
+	case model.OutcomeWalkover, model.OutcomeRetired, model.OutcomeDoubleForfeit:
+		return outcome
+	}
+	return model.OutcomeNormal
+}
+
+func outcomeLabel(outcome model.MatchOutcome) string {
+	switch normalizeOutcome(outcome) {
+	case model.OutcomeWalkover:
+		return "Walkower"
+	case model.OutcomeRetired:
+		return "Krecz"
+	case model.OutcomeDoubleForfeit:
+		return "Obustronny walkower"
+	}
+	return "Mecz rozegrany"
+}
+
+func concededSide(playerAID, playerBID, concededBy string) string {
+	switch concededBy {
+	case "":
+		return ""
+	case playerAID:
+		return sideA
+	case playerBID:
+		return sideB
+	}
+	return ""
+}
+
+// computeMatchResult turns a recorded result into table statistics. When the
+// format is known, walkover and retirement winners are credited with the sets
+// they still needed to win the match. Handicap head starts are not counted as
+// points won.
+func computeMatchResult(sets []model.SetScore, outcome model.MatchOutcome, conceded string, format SetFormat, scoring model.ScoringRules) matchResult {
+	result := matchResult{}
+	outcome = normalizeOutcome(outcome)
+	pointsToWin := format.PointsToWin
+	if pointsToWin == 0 {
+		pointsToWin = defaultPointsPerSet
+	}
+
+	if outcome != model.OutcomeWalkover && outcome != model.OutcomeDoubleForfeit {
+		for _, set := range sets {
+			result.PointsA += max(set.A-format.HeadStartA, 0)
+			result.PointsB += max(set.B-format.HeadStartB, 0)
+			if outcome == model.OutcomeRetired && validateSetScore(set, SetFormat{PointsToWin: pointsToWin}) != "" {
+				continue
+			}
+			if set.A > set.B {
+				result.SetsA++
+			} else {
+				result.SetsB++
+			}
+		}
+	}
+
+	switch outcome {
+	case model.OutcomeDoubleForfeit:
+		result.LeaguePointsA = scoring.Forfeit
+		result.LeaguePointsB = scoring.Forfeit
+		return result
+	case model.OutcomeWalkover, model.OutcomeRetired:
+		result.Winner = sideA
+		if conceded == sideA {
+			result.Winner = sideB
+		}
+		if format.BestOf > 0 {
+			if result.Winner == sideA && result.SetsA < format.SetsToWin() {
+				result.SetsA = format.SetsToWin()
+			}
+			if result.Winner == sideB && result.SetsB < format.SetsToWin() {
+				result.SetsB = format.SetsToWin()
+			}
+		}
+		loserPoints := scoring.Loss
+		if outcome == model.OutcomeWalkover {
+			loserPoints = scoring.Forfeit
+		}
+		if result.Winner == sideA {
+			result.LeaguePointsA, result.LeaguePointsB = scoring.Win, loserPoints
+		} else {
+			result.LeaguePointsA, result.LeaguePointsB = loserPoints, scoring.Win
+		}
+		return result
+	}
+
+	if result.SetsA > result.SetsB {
+		result.Winner = sideA
+		result.LeaguePointsA, result.LeaguePointsB = scoring.Win, scoring.Loss
+	} else {
+		result.Winner = sideB
+		result.LeaguePointsA, result.LeaguePointsB = scoring.Loss, scoring.Win
+	}
+	return result
+}
+
+func leagueMatchResult(match model.Match, format SetFormat, scoring model.ScoringRules) matchResult {
+	format = format.withHeadStart(match.HeadStartA, match.HeadStartB)
+	return computeMatchResult(match.Sets, match.Outcome, concededSide(match.PlayerAID, match.PlayerBID, match.ConcededBy), format, scoring)
+}
+
+func friendlyMatchResult(match model.FriendlyMatch) matchResult {
+	return computeMatchResult(match.Sets, match.Outcome, concededSide(match.PlayerAID, match.PlayerBID, match.ConcededBy), SetFormat{}, defaultScoring)
+}
+
+func formatScoreLine(sets []model.SetScore, outcome model.MatchOutcome, conceded model.User) string {
+	scoreParts := make([]string, 0, len(sets))
+	for _, set := range sets {
+		scoreParts = append(scoreParts, fmt.Sprintf("%d:%d", set.A, set.B))
+	}
+	scoreLine := strings.Join(scoreParts, ", ")
+
+	switch normalizeOutcome(outcome) {
+	case model.OutcomeWalkover:
+		return fmt.Sprintf("Walkower (nieobecność: %s)", conceded.FullName())
+	case model.OutcomeDoubleForfeit:
+		return "Obustronny walkower"
+	case model.OutcomeRetired:
+		if scoreLine == "" {
+			return fmt.Sprintf("Krecz: %s", conceded.FullName())
+		}
+		return fmt.Sprintf("%s – krecz: %s", scoreLine, conceded.FullName())
+	}
+	return scoreLine
+}
+
+func parseMatchOutcome(r *http.Request) (model.MatchOutcome, string) {
+	outcome := normalizeOutcome(model.MatchOutcome(strings.TrimSpace(r.FormValue("outcome"))))
+	side := strings.TrimSpace(r.FormValue("conceded_by"))
+	if side != sideA && side != sideB {
+		side = ""
+	}
+	if outcome == model.OutcomeNormal || outcome == model.OutcomeDoubleForfeit {
+		side = ""
+	}
+	return outcome, side
+}
+
+func sidePlayerID(side, playerAID, playerBID string) string {
+	switch side {
+	case sideA:
+		return playerAID
+	case sideB:
+		return playerBID
+	}
+	return ""
+}
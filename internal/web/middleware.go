@@ -29,5 +29,7 @@ func isPublicPath(path string) bool {
 	if path == "/login" || path == "/register" || path == "/healthz" {
 		return true
 	}
-	return strings.HasPrefix(path, "/static/")
+	// Calendar feeds are fetched by calendar apps without a session; the
+	// secret token in the URL is their only credential.
+	return strings.HasPrefix(path, "/static/") || strings.HasPrefix(path, "/calendar/")
 }
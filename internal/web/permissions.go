@@ -0,0 +1,119 @@
+package web
+
+import (
+	"net/http"
+	"slices"
+
+	"sqoush-app/internal/model"
+)
+
+func capabilityLabel(capability model.Capability) string {
+	switch capability {
+	case model.CapManagePlayers:
+		return "Zarządzanie graczami"
+	case model.CapManageAdmins:
+		return "Zarządzanie administratorami"
+	case model.CapEditResults:
+		return "Wpisywanie i poprawianie wyników"
+	case model.CapResolveDisputes:
+		return "Rozstrzyganie sporów"
+	case model.CapEndLeague:
+		return "Kończenie ligi i nowe sezony"
+	case model.CapEditSettings:
+		return "Zmiana ustawień ligi"
+	}
+	return string(capability)
+}
+
+func adminRoleLabel(role model.LeagueAdminRole) string {
+	if role == model.LeagueAdminModerator {
+		return "Moderator"
+	}
+	return "Moderator-Gracz"
+}
+
+// can reports whether the user holds the capability in the league. Super
+// admins and the owner hold all of them; other admins get what their role
+// is granted.
+func can(league model.League, user model.User, capability model.Capability) bool {
+	if isSuperAdmin(user) {
+		return true
+	}
+	if user.ID == "" {
+		return false
+	}
+	if league.OwnerID == user.ID {
+		return true
+	}
+	role, ok := league.AdminRoles[user.ID]
+	return ok && slices.Contains(league.RoleCapabilities(role), capability)
+}
+
+func leaguePermissions(league model.League, user model.User) LeaguePermissions {
+	if league.IsArchived() {
+		return LeaguePermissions{}
+	}
+	return LeaguePermissions{
+		ManagePlayers:   can(league, user, model.CapManagePlayers),
+		ManageAdmins:    can(league, user, model.CapManageAdmins),
+		EditResults:     can(league, user, model.CapEditResults),
+		ResolveDisputes: can(league, user, model.CapResolveDisputes),
+		EndLeague:       can(league, user, model.CapEndLeague),
+		EditSettings:    can(league, user, model.CapEditSettings),
+	}
+}
+
+// handlePermissionsUpdate saves the owner's capability matrix. Each role
+// sends the capabilities it keeps as repeated form values.
+func (s *Server) handlePermissionsUpdate(w http.ResponseWriter, r *http.Request) {
+	league, ok := s.ownedLeague(w, r)
+	if !ok {
+		return
+	}
+	if league.IsArchived() {
+		http.Error(w, "liga jest zarchiwizowana", http.StatusBadRequest)
+		return
+	}
+	permissions := map[model.LeagueAdminRole][]model.Capability{}
+	for _, role := range model.LeagueAdminRoles {
+		granted := []model.Capability{}
+		for _, value := range r.Form["cap_"+string(role)] {
+			if capability := model.Capability(value); slices.Contains(model.Capabilities, capability) && !slices.Contains(granted, capability) {
+				granted = append(granted, capability)
+			}
+		}
+		permissions[role] = granted
+	}
+	if r.FormValue("action") == "reset" {
+		permissions = nil
+	}
+	league.Permissions = permissions
+	if err := s.store.UpdateLeague(league); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	http.Redirect(w, r, "/leagues/"+league.ID+"?notice=permissions_saved#permissions", http.StatusSeeOther)
+}
+
+func permissionMatrix(league model.League) []PermissionRowView {
+	rows := []PermissionRowView{}
+	for _, capability := range model.Capabilities {
+		row := PermissionRowView{Capability: capability, Label: capabilityLabel(capability)}
+		for _, role := range model.LeagueAdminRoles {
+			row.Cells = append(row.Cells, PermissionCellView{
+				Role:    role,
+				Granted: slices.Contains(league.RoleCapabilities(role), capability),
+			})
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func permissionRoles() []PermissionRoleView {
+	roles := []PermissionRoleView{}
+	for _, role := range model.LeagueAdminRoles {
+		roles = append(roles, PermissionRoleView{Role: role, Label: adminRoleLabel(role)})
+	}
+	return roles
+}
@@ -0,0 +1,157 @@
+package web
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/rating"
+)
+
+// progressWindow is how many of the latest games the rolling win rate and
+// points per set cover.
+const progressWindow = 10
+
+// PlayerProgress is the history behind a player's profile charts, also served
+// as JSON.
+type PlayerProgress struct {
+	PlayerID      string                `json:"playerId"`
+	Name          string                `json:"name"`
+	Rating        ProgressRating        `json:"rating"`
+	RatingHistory []ProgressRatingPoint `json:"ratingHistory"`
+	Games         []ProgressGame        `json:"games"`
+	Window        int                   `json:"window"`
+}
+
+type ProgressRating struct {
+	Rating      float64 `json:"rating"`
+	Deviation   float64 `json:"deviation"`
+	Volatility  float64 `json:"volatility"`
+	Games       int     `json:"games"`
+	Provisional bool    `json:"provisional"`
+}
+
+type ProgressRatingPoint struct {
+	At        time.Time `json:"at"`
+	Rating    float64   `json:"rating"`
+	Deviation float64   `json:"deviation"`
+}
+
+// ProgressGame is one rated game seen from the player's side. WinRate and
+// PointsPerSet are rolling values over the window ending with this game.
+type ProgressGame struct {
+	ID           string    `json:"id"`
+	PlayedAt     time.Time `json:"playedAt"`
+	Opponents    []string  `json:"opponents"`
+	Won          bool      `json:"won"`
+	SetsWon      int       `json:"setsWon"`
+	SetsLost     int       `json:"setsLost"`
+	PointsWon    int       `json:"pointsWon"`
+	PointsLost   int       `json:"pointsLost"`
+	WinRate      float64   `json:"winRate"`
+	PointsPerSet float64   `json:"pointsPerSet"`
+}
+
+// playerProgress shows the player's public rating, the same one league
+// tables show. The history, game count and games are built only from results
+// of leagues the viewer may see, so they reveal nothing about the others.
+func (s *Server) playerProgress(player, viewer model.User, now time.Time) PlayerProgress {
+	results := []ratedResult{}
+	for _, result := range s.ratedResults() {
+		if result.League == nil || canViewLeague(*result.League, viewer) {
+			results = append(results, result)
+		}
+	}
+	games := ratedGames(results)
+
+	progress := PlayerProgress{
+		PlayerID:      player.ID,
+		Name:          player.FullName(),
+		RatingHistory: []ProgressRatingPoint{},
+		Games:         []ProgressGame{},
+		Window:        progressWindow,
+	}
+	current, ok := s.playerRatings()[player.ID]
+	if !ok {
+		current = rating.Default()
+	}
+	progress.Rating = ProgressRating{
+		Rating:      round1(current.Rating),
+		Deviation:   round1(current.Deviation),
+		Volatility:  current.Volatility,
+		Provisional: current.Provisional(),
+	}
+	for _, point := range rating.History(games, player.ID, now) {
+		progress.RatingHistory = append(progress.RatingHistory, ProgressRatingPoint{
+			At:        point.At,
+			Rating:    round1(point.Rating.Rating),
+			Deviation: round1(point.Deviation),
+		})
+	}
+
+	played := []ratedResult{}
+	for _, result := range results {
+		if slices.Contains(result.Game.SideA, player.ID) || slices.Contains(result.Game.SideB, player.ID) {
+			played = append(played, result)
+		}
+	}
+	sort.SliceStable(played, func(i, j int) bool {
+		if !played[i].Game.PlayedAt.Equal(played[j].Game.PlayedAt) {
+			return played[i].Game.PlayedAt.Before(played[j].Game.PlayedAt)
+		}
+		return played[i].Game.ID < played[j].Game.ID
+	})
+	for _, result := range played {
+		progress.Games = append(progress.Games, s.progressGame(player.ID, result))
+	}
+	progress.Rating.Games = len(progress.Games)
+	for i := range progress.Games {
+		window := progress.Games[max(0, i+1-progressWindow) : i+1]
+		progress.Games[i].WinRate, progress.Games[i].PointsPerSet = rollingForm(window)
+	}
+	return progress
+}
+
+func (s *Server) progressGame(playerID string, result ratedResult) ProgressGame {
+	game := ProgressGame{ID: result.Game.ID, PlayedAt: result.Game.PlayedAt, Opponents: []string{}}
+	mySide, opponents := sideA, result.Game.SideB
+	game.SetsWon, game.SetsLost = result.Result.SetsA, result.Result.SetsB
+	game.PointsWon, game.PointsLost = result.Result.PointsA, result.Result.PointsB
+	if !slices.Contains(result.Game.SideA, playerID) {
+		mySide, opponents = sideB, result.Game.SideA
+		game.SetsWon, game.SetsLost = game.SetsLost, game.SetsWon
+		game.PointsWon, game.PointsLost = game.PointsLost, game.PointsWon
+	}
+	game.Won = result.Result.Winner == mySide
+	for _, user := range s.usersByID(opponents) {
+		game.Opponents = append(game.Opponents, user.FullName())
+	}
+	return game
+}
+
+// rollingForm returns the win rate in percent and the average points won per
+// set over the games.
+func rollingForm(games []ProgressGame) (float64, float64) {
+	if len(games) == 0 {
+		return 0, 0
+	}
+	wins, sets, points := 0, 0, 0
+	for _, game := range games {
+		if game.Won {
+			wins++
+		}
+		sets += game.SetsWon + game.SetsLost
+		points += game.PointsWon
+	}
+	winRate := round1(float64(wins) * 100 / float64(len(games)))
+	if sets == 0 {
+		return winRate, 0
+	}
+	return winRate, round1(float64(points) / float64(sets))
+}
+
+func round1(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,115 @@
+package web
+
+import (
+	"sync"
+	"time"
+
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/rating"
+)
+
+// ratingCache holds the last computed ratings until a result is written or
+// a new rating period starts.
+type ratingCache struct {
+	mu      sync.Mutex
+	version int64
+	expires time.Time
+	ratings map[string]rating.Rating
+}
+
+// playerRatings rates every player from all confirmed league and friendly
+// results. The whole history is replayed only when the store reports a
+// changed result, so edited results never leave stale ratings behind.
+func (s *Server) playerRatings() map[string]rating.Rating {
+	now := time.Now()
+	version, ok := s.store.ResultsVersion()
+	if !ok {
+		return rating.Compute(ratedGames(s.ratedResults()), now)
+	}
+	cache := s.ratings
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if cache.ratings != nil && cache.version == version && (cache.expires.IsZero() || now.Before(cache.expires)) {
+		return cache.ratings
+	}
+	games := ratedGames(s.ratedResults())
+	cache.ratings = rating.Compute(games, now)
+	cache.version = version
+	cache.expires = rating.Expires(games, now)
+	return cache.ratings
+}
+
+// ratedResult is a confirmed result that counts towards ratings, with the
+// score kept for progress charts. League is empty for friendlies.
+type ratedResult struct {
+	Game   rating.Game
+	Result matchResult
+	League *model.League
+}
+
+func ratedGames(results []ratedResult) []rating.Game {
+	games := make([]rating.Game, 0, len(results))
+	for _, result := range results {
+		games = append(games, result.Game)
+	}
+	return games
+}
+
+// ratedResults lists the results that count towards ratings. Walkovers are
+// left out because nothing was played.
+func (s *Server) ratedResults() []ratedResult {
+	results := []ratedResult{}
+	for _, league := range s.store.ListLeagues() {
+		format := setFormatForLeague(league)
+		scoring := scoringForLeague(league)
+		for _, match := range s.store.ListMatches(league.ID) {
+			if match.Status != model.MatchConfirmed || !ratedOutcome(match.Outcome) {
+				continue
+			}
+			result := leagueMatchResult(match, format, scoring)
+			if game, ok := ratedGame("match-"+match.ID, match.ReportTime(), match.SideA(), match.SideB(), result); ok {
+				results = append(results, ratedResult{Game: game, Result: result, League: &league})
+			}
+		}
+	}
+	for _, match := range s.store.ListFriendlyMatches() {
+		if match.Status != model.MatchConfirmed || !ratedOutcome(match.Outcome) {
+			continue
+		}
+		result := friendlyMatchResult(match)
+		if game, ok := ratedGame("friendly-"+match.ID, match.PlayedAt, match.SideA(), match.SideB(), result); ok {
+			results = append(results, ratedResult{Game: game, Result: result})
+		}
+	}
+	return results
+}
+
+func ratedOutcome(outcome model.MatchOutcome) bool {
+	switch normalizeOutcome(outcome) {
+	case model.OutcomeWalkover, model.OutcomeDoubleForfeit:
+		return false
+	}
+	return true
+}
+
+func ratedGame(id string, playedAt time.Time, sideAIDs, sideBIDs []string, result matchResult) (rating.Game, bool) {
+	game := rating.Game{ID: id, PlayedAt: playedAt, SideA: sideAIDs, SideB: sideBIDs}
+	switch result.Winner {
+	case sideA:
+		game.Score = 1
+	case sideB:
+		game.Score = 0
+	default:
+		return rating.Game{}, false
+	}
+	return game, true
+}
+
+// withRatings fills in the current rating of every player in a table. Pair
+// rows have no rating of their own and stay empty.
+func withRatings(entries []StandingEntry, ratings map[string]rating.Rating) []StandingEntry {
+	for i := range entries {
+		entries[i].Rating = ratings[entries[i].Player.ID]
+	}
+	return entries
+}
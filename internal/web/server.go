@@ -3,6 +3,7 @@ package web
 import (
 	"net/http"
 
+	"sqoush-app/internal/booking"
 	"sqoush-app/internal/store"
 
 	"github.com/go-chi/chi/v5"
@@ -11,10 +12,17 @@ import (
 type Server struct {
 	store     store.Store
 	templates *Templates
+	bookings  booking.Provider
+	ratings   *ratingCache
 }
 
-func NewServer(store store.Store, templates *Templates) *Server {
-	return &Server{store: store, templates: templates}
+// NewServer wires the handlers. Without a booking provider courts are booked
+// in the app's own store.
+func NewServer(store store.Store, templates *Templates, bookings booking.Provider) *Server {
+	if bookings == nil {
+		bookings = booking.NewLocal(store)
+	}
+	return &Server{store: store, templates: templates, bookings: bookings, ratings: &ratingCache{}}
 }
 
 func (s *Server) Routes() http.Handler {
@@ -33,6 +41,9 @@ func (s *Server) Routes() http.Handler {
 	r.Get("/register", s.handleRegister)
 	r.Post("/register", s.handleRegisterPost)
 	r.Post("/logout", s.handleLogout)
+	r.Post("/calendar", s.handleCalendarFeedCreate)
+	r.Get("/calendar/user/{feed}", s.handleUserCalendar)
+	r.Get("/calendar/league/{feed}", s.handleLeagueCalendar)
 	r.Get("/friendlies/new", s.handleFriendlyNew)
 	r.Get("/friendlies/search", s.handleFriendlySearch)
 	r.Get("/friendlies/select", s.handleFriendlySelect)
@@ -43,23 +54,84 @@ func (s *Server) Routes() http.Handler {
 	r.Get("/reports/new", s.handleReportNew)
 	r.Post("/reports", s.handleReportCreate)
 	r.Get("/reports", s.handleReportsList)
+	r.Get("/players/{userID}", s.handlePlayerShow)
+	r.Get("/players/{userID}/progress", s.handlePlayerProgress)
+	r.Get("/clubs", s.handleClubs)
+	r.Post("/clubs", s.handleClubCreate)
+	r.Get("/clubs/{clubID}", s.handleClubShow)
+	r.Post("/clubs/{clubID}", s.handleClubUpdate)
+	r.Post("/clubs/{clubID}/hours", s.handleClubHours)
+	r.Post("/clubs/{clubID}/courts", s.handleClubCourtAdd)
+	r.Post("/clubs/{clubID}/courts/{courtID}/remove", s.handleClubCourtRemove)
+	r.Post("/clubs/{clubID}/admins", s.handleClubAdminAdd)
+	r.Post("/clubs/{clubID}/admins/{userID}/remove", s.handleClubAdminRemove)
+	r.Post("/clubs/{clubID}/bookings", s.handleCourtBook)
+	r.Post("/clubs/{clubID}/bookings/{bookingID}/cancel", s.handleCourtBookingCancel)
 	r.Get("/leagues/new", s.handleLeagueNew)
 	r.Get("/leagues/search", s.handleLeagueSearch)
 	r.Get("/leagues/search/results", s.handleLeagueSearchResults)
 	r.Post("/leagues/{leagueID}/join", s.handleLeagueJoin)
+	r.Post("/leagues/join-code", s.handleJoinByCode)
+	r.Get("/invites/{token}", s.handleInviteShow)
+	r.Post("/invites/{token}", s.handleInviteAccept)
 	r.Post("/leagues", s.handleLeagueCreate)
 	r.Get("/leagues/{leagueID}", s.handleLeagueShow)
 	r.Get("/leagues/{leagueID}/players/search", s.handlePlayerSearch)
 	r.Post("/leagues/{leagueID}/players", s.handlePlayerAdd)
+	r.Post("/leagues/{leagueID}/players/{userID}/remove", s.handlePlayerRemove)
+	r.Post("/leagues/{leagueID}/players/{userID}/suspend", s.handlePlayerSuspend)
+	r.Post("/leagues/{leagueID}/players/{userID}/reinstate", s.handlePlayerReinstate)
+	r.Post("/leagues/{leagueID}/withdraw", s.handleLeagueWithdraw)
 	r.Post("/leagues/{leagueID}/admins", s.handleLeagueAdminAdd)
 	r.Post("/leagues/{leagueID}/admins/{adminID}/role", s.handleLeagueAdminRoleUpdate)
 	r.Post("/leagues/{leagueID}/admins/{adminID}/remove", s.handleLeagueAdminRemove)
 	r.Post("/leagues/{leagueID}/join-requests/{requestID}/approve", s.handleJoinRequestApprove)
 	r.Post("/leagues/{leagueID}/join-requests/{requestID}/reject", s.handleJoinRequestReject)
 	r.Post("/leagues/{leagueID}/end", s.handleLeagueEnd)
+	r.Post("/leagues/{leagueID}/next-season", s.handleLeagueNextSeason)
+	r.Post("/leagues/{leagueID}/divisions", s.handleDivisionCreate)
+	r.Post("/leagues/{leagueID}/divisions/assign", s.handleDivisionAssign)
+	r.Post("/leagues/{leagueID}/divisions/promotion", s.handleDivisionPromotion)
+	r.Post("/leagues/{leagueID}/divisions/{divisionID}/remove", s.handleDivisionRemove)
+	r.Post("/leagues/{leagueID}/handicaps", s.handleHandicapUpdate)
+	r.Post("/leagues/{leagueID}/visibility", s.handleLeagueVisibility)
+	r.Post("/leagues/{leagueID}/join-code", s.handleJoinCodeUpdate)
+	r.Post("/leagues/{leagueID}/invites", s.handleInviteCreate)
+	r.Post("/leagues/{leagueID}/invites/{inviteID}/revoke", s.handleInviteRevoke)
+	r.Post("/leagues/{leagueID}/capacity", s.handleCapacityUpdate)
+	r.Get("/leagues/{leagueID}/edit", s.handleLeagueEdit)
+	r.Post("/leagues/{leagueID}/edit", s.handleLeagueUpdate)
+	r.Post("/leagues/{leagueID}/archive", s.handleLeagueArchive)
+	r.Post("/leagues/{leagueID}/restore", s.handleLeagueRestore)
+	r.Post("/leagues/{leagueID}/ownership/nominate", s.handleOwnershipNominate)
+	r.Post("/leagues/{leagueID}/ownership/cancel", s.handleOwnershipCancel)
+	r.Post("/leagues/{leagueID}/ownership/respond", s.handleOwnershipRespond)
+	r.Post("/leagues/{leagueID}/ownership/override", s.handleOwnershipOverride)
+	r.Post("/leagues/{leagueID}/permissions", s.handlePermissionsUpdate)
+	r.Post("/leagues/{leagueID}/schedule", s.handleScheduleProposal)
+	r.Post("/leagues/{leagueID}/calendar", s.handleLeagueCalendarFeedCreate)
+	r.Post("/leagues/{leagueID}/waitlist/accept", s.handleWaitlistAccept)
+	r.Post("/leagues/{leagueID}/waitlist/leave", s.handleWaitlistLeave)
+	r.Post("/leagues/{leagueID}/waitlist/{userID}/remove", s.handleWaitlistRemove)
+	r.Post("/leagues/{leagueID}/teams", s.handleTeamCreate)
+	r.Post("/leagues/{leagueID}/teams/{teamID}/players", s.handleTeamPlayerAdd)
+	r.Post("/leagues/{leagueID}/fixtures", s.handleFixtureCreate)
+	r.Get("/fixtures/{fixtureID}", s.handleFixtureShow)
+	r.Post("/fixtures/{fixtureID}/order", s.handleFixtureOrder)
+	r.Post("/fixtures/{fixtureID}/rubbers/{matchID}/result", s.handleRubberReport)
 	r.Post("/leagues/{leagueID}/matches", s.handleMatchCreate)
+	r.Get("/matches/{matchID}", s.handleMatchShow)
+	r.Post("/matches/{matchID}/edit", s.handleMatchEdit)
 	r.Post("/matches/{matchID}/confirm", s.handleMatchConfirm)
 	r.Post("/matches/{matchID}/reject", s.handleMatchReject)
+	r.Post("/matches/{matchID}/dispute/accept", s.handleMatchDisputeAccept)
+	r.Post("/matches/{matchID}/dispute/escalate", s.handleMatchDisputeEscalate)
+	r.Post("/matches/{matchID}/dispute/resolve", s.handleMatchDisputeResolve)
+	r.Post("/matches/{matchID}/schedule", s.handleScheduleCounter)
+	r.Post("/matches/{matchID}/schedule/accept", s.handleScheduleAccept)
+	r.Post("/matches/{matchID}/schedule/cancel", s.handleScheduleCancel)
+	r.Post("/matches/{matchID}/report", s.handleScheduledReport)
+	r.Post("/matches/{matchID}/booking", s.handleMatchCourtBook)
 
 	return r
 }
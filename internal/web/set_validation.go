@@ -0,0 +1,203 @@
+package web
+
+import (
+	"fmt"
+
+	"sqoush-app/internal/model"
+)
+
+const (
+	defaultPointsPerSet = 11
+	defaultBestOf       = 5
+)
+
+type SetFormat struct {
+	BestOf      int
+	PointsToWin int
+	// HeadStartA and HeadStartB are the points each side starts every set
+	// with in a handicap league.
+	HeadStartA int
+	HeadStartB int
+}
+
+type SetError struct {
+	Set     int
+	Message string
+}
+
+func (e SetError) String() string {
+	if e.Set == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("Set %d: %s", e.Set, e.Message)
+}
+
+func setFormatForLeague(league model.League) SetFormat {
+	return SetFormat{BestOf: league.SetsPerMatch, PointsToWin: league.PointsPerSet}.normalized()
+}
+
+func matchSetFormat(league model.League, match model.Match) SetFormat {
+	return setFormatForLeague(league).withHeadStart(match.HeadStartA, match.HeadStartB)
+}
+
+func (f SetFormat) withHeadStart(a, b int) SetFormat {
+	f.HeadStartA, f.HeadStartB = a, b
+	return f
+}
+
+func (f SetFormat) normalized() SetFormat {
+	if f.BestOf < 1 {
+		f.BestOf = defaultBestOf
+	}
+	if f.PointsToWin != 15 {
+		f.PointsToWin = defaultPointsPerSet
+	}
+	return f
+}
+
+func (f SetFormat) SetsToWin() int {
+	return f.BestOf/2 + 1
+}
+
+func (f SetFormat) Label() string {
+	return fmt.Sprintf("Best of %d, sety do %d", f.BestOf, f.PointsToWin)
+}
+
+func validateSetScore(set model.SetScore, format SetFormat) string {
+	if set.A < 0 || set.B < 0 {
+		return "wynik nie może być ujemny"
+	}
+	if set.A < format.HeadStartA || set.B < format.HeadStartB {
+		return fmt.Sprintf("z forą set zaczyna się od stanu %d:%d", format.HeadStartA, format.HeadStartB)
+	}
+	if set.A == set.B {
+		return "set musi mieć zwycięzcę"
+	}
+	winner, loser := set.A, set.B
+	if loser > winner {
+		winner, loser = loser, winner
+	}
+	if winner < format.PointsToWin {
+		return fmt.Sprintf("zwycięzca seta musi zdobyć co najmniej %d punktów", format.PointsToWin)
+	}
+	if loser >= format.PointsToWin-1 {
+		if winner-loser != 2 {
+			return fmt.Sprintf("przy stanie %d:%d set trwa do przewagi dwóch punktów", format.PointsToWin-1, format.PointsToWin-1)
+		}
+		return ""
+	}
+	if winner != format.PointsToWin {
+		return fmt.Sprintf("set kończy się po zdobyciu %d punktów", format.PointsToWin)
+	}
+	return ""
+}
+
+func validateSets(sets []model.SetScore, format SetFormat) []SetError {
+	format = format.normalized()
+	errs := []SetError{}
+	if len(sets) == 0 {
+		return append(errs, SetError{Message: "Uzupełnij wynik przynajmniej jednego seta."})
+	}
+	if len(sets) > format.BestOf {
+		errs = append(errs, SetError{Message: fmt.Sprintf("Mecz do %d wygranych setów może mieć najwyżej %d setów.", format.SetsToWin(), format.BestOf)})
+	}
+	setsWonA, setsWonB := 0, 0
+	decidedAfter := 0
+	for i, set := range sets {
+		if msg := validateSetScore(set, format); msg != "" {
+			errs = append(errs, SetError{Set: i + 1, Message: msg})
+		}
+		if decidedAfter > 0 {
+			errs = append(errs, SetError{Set: i + 1, Message: fmt.Sprintf("mecz został rozstrzygnięty po secie %d", decidedAfter)})
+			continue
+		}
+		if set.A > set.B {
+			setsWonA++
+		} else if set.B > set.A {
+			setsWonB++
+		}
+		if setsWonA == format.SetsToWin() || setsWonB == format.SetsToWin() {
+			decidedAfter = i + 1
+		}
+	}
+	if decidedAfter == 0 {
+		errs = append(errs, SetError{Message: fmt.Sprintf("Mecz nie został rozstrzygnięty: zwycięzca potrzebuje %d wygranych setów.", format.SetsToWin())})
+	}
+	return errs
+}
+
+func setErrorMessages(errs []SetError) []string {
+	messages := make([]string, 0, len(errs))
+	for _, err := range errs {
+		messages = append(messages, err.String())
+	}
+	return messages
+}
+
+func validateMatchResult(sets []model.SetScore, outcome model.MatchOutcome, conceded string, format SetFormat) []SetError {
+	format = format.normalized()
+	switch normalizeOutcome(outcome) {
+	case model.OutcomeWalkover:
+		errs := []SetError{}
+		if conceded == "" {
+			errs = append(errs, SetError{Message: "Wskaż gracza, który nie stawił się na mecz."})
+		}
+		if len(sets) > 0 {
+			errs = append(errs, SetError{Message: "Walkower nie może zawierać wyniku setów."})
+		}
+		return errs
+	case model.OutcomeDoubleForfeit:
+		if len(sets) > 0 {
+			return []SetError{{Message: "Obustronny walkower nie może zawierać wyniku setów."}}
+		}
+		return []SetError{}
+	case model.OutcomeRetired:
+		return validateRetiredSets(sets, conceded, format)
+	}
+	return validateSets(sets, format)
+}
+
+// validateRetiredSets accepts a score where only the last set may be
+// unfinished and neither player had won the match before the retirement.
+func validateRetiredSets(sets []model.SetScore, conceded string, format SetFormat) []SetError {
+	errs := []SetError{}
+	if conceded == "" {
+		errs = append(errs, SetError{Message: "Wskaż gracza, który skreczował."})
+	}
+	if len(sets) > format.BestOf {
+		errs = append(errs, SetError{Message: fmt.Sprintf("Mecz do %d wygranych setów może mieć najwyżej %d setów.", format.SetsToWin(), format.BestOf)})
+	}
+	setsWonA, setsWonB := 0, 0
+	for i, set := range sets {
+		msg := validateSetScore(set, format)
+		if msg == "" {
+			if set.A > set.B {
+				setsWonA++
+			} else {
+				setsWonB++
+			}
+			if setsWonA == format.SetsToWin() || setsWonB == format.SetsToWin() {
+				errs = append(errs, SetError{Set: i + 1, Message: "mecz był już rozstrzygnięty przed kreczem"})
+			}
+			continue
+		}
+		if i < len(sets)-1 || !unfinishedSet(set, format) {
+			errs = append(errs, SetError{Set: i + 1, Message: msg})
+		}
+	}
+	return errs
+}
+
+func unfinishedSet(set model.SetScore, format SetFormat) bool {
+	if set.A < format.HeadStartA || set.B < format.HeadStartB {
+		return false
+	}
+	winner, loser := set.A, set.B
+	if loser > winner {
+		winner, loser = loser, winner
+	}
+	if winner < format.PointsToWin {
+		return true
+	}
+	return loser >= format.PointsToWin-1 && winner-loser <= 1
+}
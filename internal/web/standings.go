@@ -1,69 +1,342 @@
 package web
 
 import (
+	"fmt"
 	"sort"
+	"strings"
 
 	"sqoush-app/internal/model"
 )
 
-func BuildStandings(players []model.User, matches []model.Match) []StandingEntry {
+type StandingsOptions struct {
+	TieBreakers []model.TieBreakRule
+	Format      SetFormat
+	Scoring     model.ScoringRules
+}
+
+type tieBreakPreset struct {
+	Value string
+	Label string
+	Rules []model.TieBreakRule
+}
+
+var defaultTieBreakers = []model.TieBreakRule{
+	model.TieBreakSetDifference,
+	model.TieBreakPointDifference,
+}
+
+var tieBreakPresets = []tieBreakPreset{
+	{
+		Value: "classic",
+		Label: "Bilans setów, potem bilans punktów",
+		Rules: defaultTieBreakers,
+	},
+	{
+		Value: "squash",
+		Label: "Bezpośredni mecz / mała tabela, potem bilanse",
+		Rules: []model.TieBreakRule{
+			model.TieBreakHeadToHead,
+			model.TieBreakMiniLeague,
+			model.TieBreakSetDifference,
+			model.TieBreakPointDifference,
+		},
+	},
+}
+
+func standingsOptionsForLeague(league model.League) StandingsOptions {
+	return StandingsOptions{
+		TieBreakers: league.TieBreakers,
+		Format:      setFormatForLeague(league),
+		Scoring:     scoringForLeague(league),
+	}
+}
+
+func parseTieBreakers(value string) []model.TieBreakRule {
+	value = strings.TrimSpace(value)
+	for _, preset := range tieBreakPresets {
+		if preset.Value == value {
+			return append([]model.TieBreakRule{}, preset.Rules...)
+		}
+	}
+	return append([]model.TieBreakRule{}, defaultTieBreakers...)
+}
+
+func tieBreakLabel(rule model.TieBreakRule) string {
+	switch rule {
+	case model.TieBreakHeadToHead:
+		return "Bezpośredni mecz"
+	case model.TieBreakMiniLeague:
+		return "Mała tabela"
+	case model.TieBreakSetDifference:
+		return "Bilans setów"
+	case model.TieBreakPointDifference:
+		return "Bilans punktów"
+	}
+	return string(rule)
+}
+
+func tieBreakLabels(rules []model.TieBreakRule) []string {
+	if len(rules) == 0 {
+		rules = defaultTieBreakers
+	}
+	labels := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		labels = append(labels, tieBreakLabel(rule))
+	}
+	return labels
+}
+
+func BuildStandings(players []model.User, matches []model.Match, opts StandingsOptions) []StandingEntry {
 	index := make(map[string]*StandingEntry)
 	for _, p := range players {
 		entry := &StandingEntry{Player: p}
 		index[p.ID] = entry
 	}
 
+	scoring := opts.Scoring
+	if scoring.IsZero() {
+		scoring = defaultScoring
+	}
+	counted := make([]countedMatch, 0, len(matches))
 	for _, match := range matches {
 		if match.Status != model.MatchConfirmed {
 			continue
 		}
-		entryA := index[match.PlayerAID]
-		entryB := index[match.PlayerBID]
-		if entryA == nil || entryB == nil {
+		entriesA := sideEntries(index, match.SideA())
+		entriesB := sideEntries(index, match.SideB())
+		if entriesA == nil || entriesB == nil {
 			continue
 		}
-		entryA.Matches++
-		entryB.Matches++
-
-		setsWonA := 0
-		setsWonB := 0
-		for _, set := range match.Sets {
-			entryA.PointsWon += set.A
-			entryA.PointsLost += set.B
-			entryB.PointsWon += set.B
-			entryB.PointsLost += set.A
-			if set.A > set.B {
-				setsWonA++
-			} else {
-				setsWonB++
-			}
+		result := leagueMatchResult(match, opts.Format, scoring)
+		counted = append(counted, countedMatch{match: match, result: result})
+		for _, entry := range entriesA {
+			entry.addResult(result, sideA)
 		}
-		entryA.SetsWon += setsWonA
-		entryA.SetsLost += setsWonB
-		entryB.SetsWon += setsWonB
-		entryB.SetsLost += setsWonA
-
-		if setsWonA > setsWonB {
-			entryA.Points += 3
-			entryB.Points += 1
-		} else {
-			entryB.Points += 3
-			entryA.Points += 1
+		for _, entry := range entriesB {
+			entry.addResult(result, sideB)
 		}
 	}
 
-	standings := make([]StandingEntry, 0, len(players))
+	entries := make([]*StandingEntry, 0, len(index))
 	for _, entry := range index {
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Points != entries[j].Points {
+			return entries[i].Points > entries[j].Points
+		}
+		return entries[i].Player.FullName() < entries[j].Player.FullName()
+	})
+
+	rules := opts.TieBreakers
+	if len(rules) == 0 {
+		rules = defaultTieBreakers
+	}
+	resolver := tieResolver{rules: rules, matches: counted}
+	ordered := make([]*StandingEntry, 0, len(entries))
+	for start := 0; start < len(entries); {
+		end := start + 1
+		for end < len(entries) && entries[end].Points == entries[start].Points {
+			end++
+		}
+		ordered = append(ordered, resolver.resolve(entries[start:end])...)
+		start = end
+	}
+
+	standings := make([]StandingEntry, 0, len(ordered))
+	for i, entry := range ordered {
+		entry.Position = i + 1
 		standings = append(standings, *entry)
 	}
-	sort.Slice(standings, func(i, j int) bool {
-		if standings[i].Points == standings[j].Points {
-			if standings[i].SetsWon-standings[i].SetsLost == standings[j].SetsWon-standings[j].SetsLost {
-				return standings[i].PointsWon-standings[i].PointsLost > standings[j].PointsWon-standings[j].PointsLost
+	return standings
+}
+
+// sideEntries returns the table entries of every player on a match side, or
+// nil when one of them is not part of the table.
+func sideEntries(index map[string]*StandingEntry, playerIDs []string) []*StandingEntry {
+	entries := make([]*StandingEntry, 0, len(playerIDs))
+	for _, id := range playerIDs {
+		entry := index[id]
+		if entry == nil {
+			return nil
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (e *StandingEntry) addResult(result matchResult, side string) {
+	e.Matches++
+	if side == sideA {
+		e.PointsWon += result.PointsA
+		e.PointsLost += result.PointsB
+		e.SetsWon += result.SetsA
+		e.SetsLost += result.SetsB
+		e.Points += result.LeaguePointsA
+	} else {
+		e.PointsWon += result.PointsB
+		e.PointsLost += result.PointsA
+		e.SetsWon += result.SetsB
+		e.SetsLost += result.SetsA
+		e.Points += result.LeaguePointsB
+	}
+	if result.Winner == side {
+		e.Wins++
+	} else {
+		e.Losses++
+	}
+}
+
+type countedMatch struct {
+	match  model.Match
+	result matchResult
+}
+
+// tieResolver orders players level on points. When a rule separates only part
+// of a group, every remaining sub-group is resolved again from the first rule,
+// so a pair left over from a mini-league is decided by their head-to-head.
+type tieResolver struct {
+	rules   []model.TieBreakRule
+	matches []countedMatch
+}
+
+func (t tieResolver) resolve(group []*StandingEntry) []*StandingEntry {
+	if len(group) < 2 {
+		return group
+	}
+	for _, rule := range t.rules {
+		keys, ok := t.keys(rule, group)
+		if !ok {
+			continue
+		}
+		buckets := splitByKey(group, keys)
+		if len(buckets) < 2 {
+			continue
+		}
+		result := make([]*StandingEntry, 0, len(group))
+		for _, bucket := range buckets {
+			for _, entry := range bucket {
+				appendTieBreakNote(entry, rule, tieBreakNote(rule, keys[entry.Player.ID], len(group)))
 			}
-			return standings[i].SetsWon-standings[i].SetsLost > standings[j].SetsWon-standings[j].SetsLost
+			result = append(result, t.resolve(bucket)...)
+		}
+		return result
+	}
+	for _, entry := range group {
+		entry.TieBreakNote = joinNote(entry.TieBreakNote, "Remis nierozstrzygnięty")
+	}
+	return group
+}
+
+func (t tieResolver) keys(rule model.TieBreakRule, group []*StandingEntry) (map[string]int, bool) {
+	keys := make(map[string]int, len(group))
+	switch rule {
+	case model.TieBreakHeadToHead:
+		if len(group) != 2 {
+			return nil, false
+		}
+		members := groupMembers(group)
+		for _, counted := range t.matches {
+			inA, inB := membersOnSide(members, counted.match.SideA()), membersOnSide(members, counted.match.SideB())
+			if len(inA) == 0 || len(inB) == 0 {
+				continue
+			}
+			winners := []string{}
+			switch counted.result.Winner {
+			case sideA:
+				winners = inA
+			case sideB:
+				winners = inB
+			}
+			for _, id := range winners {
+				keys[id]++
+			}
+		}
+	case model.TieBreakMiniLeague:
+		if len(group) < 3 {
+			return nil, false
+		}
+		members := groupMembers(group)
+		for _, counted := range t.matches {
+			inA, inB := membersOnSide(members, counted.match.SideA()), membersOnSide(members, counted.match.SideB())
+			if len(inA) == 0 || len(inB) == 0 {
+				continue
+			}
+			for _, id := range inA {
+				keys[id] += counted.result.LeaguePointsA
+			}
+			for _, id := range inB {
+				keys[id] += counted.result.LeaguePointsB
+			}
+		}
+	case model.TieBreakSetDifference:
+		for _, entry := range group {
+			keys[entry.Player.ID] = entry.SetsWon - entry.SetsLost
+		}
+	case model.TieBreakPointDifference:
+		for _, entry := range group {
+			keys[entry.Player.ID] = entry.PointsWon - entry.PointsLost
 		}
-		return standings[i].Points > standings[j].Points
+	default:
+		return nil, false
+	}
+	return keys, true
+}
+
+func groupMembers(group []*StandingEntry) map[string]bool {
+	members := make(map[string]bool, len(group))
+	for _, entry := range group {
+		members[entry.Player.ID] = true
+	}
+	return members
+}
+
+func membersOnSide(members map[string]bool, playerIDs []string) []string {
+	found := []string{}
+	for _, id := range playerIDs {
+		if members[id] {
+			found = append(found, id)
+		}
+	}
+	return found
+}
+
+func splitByKey(group []*StandingEntry, keys map[string]int) [][]*StandingEntry {
+	sorted := append([]*StandingEntry{}, group...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return keys[sorted[i].Player.ID] > keys[sorted[j].Player.ID]
 	})
-	return standings
+	buckets := [][]*StandingEntry{}
+	for i, entry := range sorted {
+		if i == 0 || keys[entry.Player.ID] != keys[sorted[i-1].Player.ID] {
+			buckets = append(buckets, []*StandingEntry{})
+		}
+		buckets[len(buckets)-1] = append(buckets[len(buckets)-1], entry)
+	}
+	return buckets
+}
+
+func tieBreakNote(rule model.TieBreakRule, value int, groupSize int) string {
+	switch rule {
+	case model.TieBreakHeadToHead:
+		return fmt.Sprintf("%s: %d wygr.", tieBreakLabel(rule), value)
+	case model.TieBreakMiniLeague:
+		return fmt.Sprintf("%s (%d graczy): %d pkt", tieBreakLabel(rule), groupSize, value)
+	default:
+		return fmt.Sprintf("%s: %+d", tieBreakLabel(rule), value)
+	}
+}
+
+func appendTieBreakNote(entry *StandingEntry, rule model.TieBreakRule, note string) {
+	if entry.TieBreak == "" {
+		entry.TieBreak = rule
+	}
+	entry.TieBreakNote = joinNote(entry.TieBreakNote, note)
+}
+
+func joinNote(current, note string) string {
+	if current == "" {
+		return note
+	}
+	return current + "; " + note
 }
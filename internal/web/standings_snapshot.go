@@ -0,0 +1,154 @@
+package web
+
+import (
+	"fmt"
+
+	"sqoush-app/internal/model"
+)
+
+// FreezeStandings persists the final table of a finished league together with
+// its awards. A snapshot is written once; later calls leave it untouched.
+func (s *Server) FreezeStandings(league model.League) error {
+	if _, ok := s.store.GetStandingsSnapshot(league.ID); ok {
+		return nil
+	}
+	snapshot := model.StandingsSnapshot{LeagueID: league.ID}
+	for _, group := range s.standingsGroups(league, s.store.ListMatches(league.ID)) {
+		for _, entry := range group.Standings {
+			snapshot.Entries = append(snapshot.Entries, model.StandingsSnapshotEntry{
+				Position:     entry.Position,
+				PlayerID:     entry.Player.ID,
+				PlayerName:   entry.Player.FullName(),
+				DivisionID:   group.Division.ID,
+				DivisionName: group.Division.Name,
+				Points:       entry.Points,
+				Matches:      entry.Matches,
+				Wins:         entry.Wins,
+				Losses:       entry.Losses,
+				SetsWon:      entry.SetsWon,
+				SetsLost:     entry.SetsLost,
+				PointsWon:    entry.PointsWon,
+				PointsLost:   entry.PointsLost,
+				TieBreakNote: entry.TieBreakNote,
+			})
+		}
+	}
+	var previous *model.StandingsSnapshot
+	if league.PreviousSeasonID != "" {
+		if prev, ok := s.store.GetStandingsSnapshot(league.PreviousSeasonID); ok {
+			previous = &prev
+		}
+	}
+	snapshot.Awards = standingsAwards(snapshot.Entries, previous)
+	_, err := s.store.CreateStandingsSnapshot(snapshot)
+	return err
+}
+
+// standingsAwards names the champion of every division and, when the previous
+// season's table is known, the player who climbed the most places compared to
+// it. Places are counted across divisions, top division first.
+func standingsAwards(entries []model.StandingsSnapshotEntry, previous *model.StandingsSnapshot) []model.StandingsAward {
+	awards := []model.StandingsAward{}
+	for i, entry := range entries {
+		if entry.Position != 1 || entry.Matches == 0 {
+			continue
+		}
+		if i > 0 && entries[i-1].DivisionID == entry.DivisionID {
+			continue
+		}
+		awards = append(awards, model.StandingsAward{
+			Kind:         model.AwardChampion,
+			PlayerID:     entry.PlayerID,
+			PlayerName:   entry.PlayerName,
+			DivisionName: entry.DivisionName,
+			Note:         fmt.Sprintf("%d pkt w %d meczach", entry.Points, entry.Matches),
+		})
+	}
+	if previous == nil {
+		return awards
+	}
+	previousPlaces := make(map[string]int, len(previous.Entries))
+	for i, entry := range previous.Entries {
+		previousPlaces[entry.PlayerID] = i + 1
+	}
+	var best model.StandingsSnapshotEntry
+	bestGain, bestPlace := 0, 0
+	for i, entry := range entries {
+		before, ok := previousPlaces[entry.PlayerID]
+		if !ok || entry.Matches == 0 {
+			continue
+		}
+		if gain := before - (i + 1); gain > bestGain {
+			best, bestGain, bestPlace = entry, gain, i+1
+		}
+	}
+	if bestGain > 0 {
+		awards = append(awards, model.StandingsAward{
+			Kind:       model.AwardMostImproved,
+			PlayerID:   best.PlayerID,
+			PlayerName: best.PlayerName,
+			Note:       fmt.Sprintf("awans o %d miejsc (z %d. na %d.)", bestGain, bestPlace+bestGain, bestPlace),
+		})
+	}
+	return awards
+}
+
+type snapshotRow struct {
+	StandingEntry
+	divisionID   string
+	divisionName string
+}
+
+func snapshotStandingsEntries(snapshot model.StandingsSnapshot) []snapshotRow {
+	rows := make([]snapshotRow, 0, len(snapshot.Entries))
+	for _, entry := range snapshot.Entries {
+		rows = append(rows, snapshotRow{
+			StandingEntry: StandingEntry{
+				Position:     entry.Position,
+				Player:       model.User{ID: entry.PlayerID, FirstName: entry.PlayerName},
+				Points:       entry.Points,
+				Matches:      entry.Matches,
+				Wins:         entry.Wins,
+				Losses:       entry.Losses,
+				SetsWon:      entry.SetsWon,
+				SetsLost:     entry.SetsLost,
+				PointsWon:    entry.PointsWon,
+				PointsLost:   entry.PointsLost,
+				TieBreakNote: entry.TieBreakNote,
+			},
+			divisionID:   entry.DivisionID,
+			divisionName: entry.DivisionName,
+		})
+	}
+	return rows
+}
+
+func snapshotStandings(snapshot model.StandingsSnapshot) []StandingEntry {
+	standings := []StandingEntry{}
+	for _, row := range snapshotStandingsEntries(snapshot) {
+		standings = append(standings, row.StandingEntry)
+	}
+	return standings
+}
+
+func awardLabel(award model.StandingsAward) string {
+	label := string(award.Kind)
+	switch award.Kind {
+	case model.AwardChampion:
+		label = "Mistrz ligi"
+		if award.DivisionName != "" {
+			label = "Mistrz – " + award.DivisionName
+		}
+	case model.AwardMostImproved:
+		label = "Największy postęp"
+	}
+	return label
+}
+
+func awardViews(awards []model.StandingsAward) []AwardView {
+	views := make([]AwardView, 0, len(awards))
+	for _, award := range awards {
+		views = append(views, AwardView{Label: awardLabel(award), PlayerName: award.PlayerName, Note: award.Note})
+	}
+	return views
+}
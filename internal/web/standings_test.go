@@ -0,0 +1,154 @@
+package web
+
+import (
+	"slices"
+	"testing"
+
+	"sqoush-app/internal/model"
+)
+
+var squashTieBreakers = []model.TieBreakRule{
+	model.TieBreakHeadToHead,
+	model.TieBreakMiniLeague,
+	model.TieBreakSetDifference,
+	model.TieBreakPointDifference,
+}
+
+// winsOnly scores one point per win, so a table's points are its wins.
+var winsOnly = model.ScoringRules{Win: 1}
+
+func standingsPlayers(ids ...string) []model.User {
+	players := make([]model.User, 0, len(ids))
+	for _, id := range ids {
+		players = append(players, model.User{ID: id, FirstName: id})
+	}
+	return players
+}
+
+// confirmed is a best-of-three match between a and b with the given sets,
+// each written as the points of a and then of b.
+func confirmed(a, b string, points ...int) model.Match {
+	match := model.Match{ID: a + "-" + b, PlayerAID: a, PlayerBID: b, Status: model.MatchConfirmed}
+	for i := 0; i+1 < len(points); i += 2 {
+		match.Sets = append(match.Sets, model.SetScore{A: points[i], B: points[i+1]})
+	}
+	return match
+}
+
+func TestBuildStandingsTieBreakers(t *testing.T) {
+	tests := []struct {
+		name       string
+		players    []string
+		matches    []model.Match
+		rules      []model.TieBreakRule
+		want       []string
+		unresolved []string
+	}{
+		{
+			name:    "head-to-head beats a better set difference",
+			players: []string{"a", "b", "c", "d"},
+			matches: []model.Match{
+				confirmed("a", "b", 11, 5, 5, 11, 11, 5),
+				confirmed("b", "c", 11, 5, 11, 5),
+				confirmed("d", "a", 11, 5, 11, 5),
+				confirmed("d", "c", 11, 5, 11, 5),
+			},
+			rules: squashTieBreakers,
+			want:  []string{"d", "a", "b", "c"},
+		},
+		{
+			name:    "set difference when head-to-head is not used",
+			players: []string{"a", "b", "c", "d"},
+			matches: []model.Match{
+				confirmed("a", "b", 11, 5, 5, 11, 11, 5),
+				confirmed("b", "c", 11, 5, 11, 5),
+				confirmed("d", "a", 11, 5, 11, 5),
+				confirmed("d", "c", 11, 5, 11, 5),
+			},
+			rules: defaultTieBreakers,
+			want:  []string{"d", "b", "a", "c"},
+		},
+		{
+			name:    "three-way cycle falls through to set difference",
+			players: []string{"a", "b", "c"},
+			matches: []model.Match{
+				confirmed("a", "b", 11, 5, 11, 5),
+				confirmed("b", "c", 11, 5, 5, 11, 11, 5),
+				confirmed("c", "a", 11, 5, 5, 11, 11, 5),
+			},
+			rules: squashTieBreakers,
+			want:  []string{"a", "c", "b"},
+		},
+		{
+			name:    "mini-league splits three and the pair left starts over",
+			players: []string{"a", "b", "c", "d"},
+			matches: []model.Match{
+				confirmed("a", "b", 11, 5, 11, 5),
+				confirmed("a", "c", 11, 5, 11, 5),
+				confirmed("b", "c", 11, 5, 5, 11, 11, 5),
+				confirmed("c", "b", 11, 5, 11, 5),
+				confirmed("b", "d", 11, 5, 11, 5),
+				confirmed("c", "d", 11, 5, 11, 5),
+			},
+			rules: squashTieBreakers,
+			want:  []string{"a", "c", "b", "d"},
+		},
+		{
+			name:    "identical records stay tied",
+			players: []string{"a", "b", "c"},
+			matches: []model.Match{
+				confirmed("a", "c", 11, 5, 11, 5),
+				confirmed("b", "c", 11, 5, 11, 5),
+			},
+			rules:      squashTieBreakers,
+			want:       []string{"a", "b", "c"},
+			unresolved: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standings := BuildStandings(standingsPlayers(tt.players...), tt.matches, StandingsOptions{
+				TieBreakers: tt.rules,
+				Format:      SetFormat{BestOf: 3, PointsToWin: 11},
+				Scoring:     winsOnly,
+			})
+
+			got := make([]string, 0, len(standings))
+			unresolved := []string{}
+			for i, entry := range standings {
+				if entry.Position != i+1 {
+					t.Errorf("%s has position %d at index %d", entry.Player.ID, entry.Position, i)
+				}
+				got = append(got, entry.Player.ID)
+				if entry.TieBreakNote == "Remis nierozstrzygnięty" {
+					unresolved = append(unresolved, entry.Player.ID)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("order = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(unresolved, tt.unresolved) {
+				t.Errorf("unresolved = %v, want %v", unresolved, tt.unresolved)
+			}
+		})
+	}
+}
+
+func TestBuildStandingsNotesTheDecidingRule(t *testing.T) {
+	standings := BuildStandings(standingsPlayers("a", "b", "c", "d"), []model.Match{
+		confirmed("a", "b", 11, 5, 5, 11, 11, 5),
+		confirmed("b", "c", 11, 5, 11, 5),
+		confirmed("d", "a", 11, 5, 11, 5),
+		confirmed("d", "c", 11, 5, 11, 5),
+	}, StandingsOptions{TieBreakers: squashTieBreakers, Format: SetFormat{BestOf: 3, PointsToWin: 11}, Scoring: winsOnly})
+
+	for _, entry := range standings {
+		want := model.TieBreakRule("")
+		if entry.Player.ID == "a" || entry.Player.ID == "b" {
+			want = model.TieBreakHeadToHead
+		}
+		if entry.TieBreak != want {
+			t.Errorf("%s decided by %q, want %q", entry.Player.ID, entry.TieBreak, want)
+		}
+	}
+}
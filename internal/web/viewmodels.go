@@ -1,6 +1,9 @@
 package web
 
-import "sqoush-app/internal/model"
+import (
+	"sqoush-app/internal/model"
+	"sqoush-app/internal/rating"
+)
 
 type BaseView struct {
 	Title           string
@@ -31,31 +34,186 @@ type HomeView struct {
 	DashboardPending      DashboardTabView
 	DashboardFriendly     DashboardTabView
 	LeagueSearch          LeagueSearchView
+	Upcoming              []UpcomingMatchView
+	CalendarURL           string
+	Rating                rating.Rating
+}
+
+type PlayerProfileView struct {
+	BaseView
+	Player        model.User
+	Progress      PlayerProgress
+	Wins          int
+	Losses        int
+	Leagues       []model.League
+	RecentGames   []ProgressGame
+	RatingChart   ChartView
+	WinRateChart  ChartView
+	PointsChart   ChartView
+	ProgressURL   string
+	IsCurrentUser bool
+}
+
+type ChartView struct {
+	Title     string
+	Width     int
+	Height    int
+	PlotLeft  int
+	PlotRight int
+	PlotTop   int
+	PlotBase  int
+	Line      string
+	Band      string
+	Dot       *ChartTick
+	YTicks    []ChartTick
+	XTicks    []ChartTick
+	Empty     bool
+}
+
+type ChartTick struct {
+	X     float64
+	Y     float64
+	Label string
+}
+
+type UpcomingMatchView struct {
+	Match      MatchView
+	League     model.League
+	When       string
+	NeedsReply bool
+}
+
+type ScheduleProposalView struct {
+	ProposedBy model.User
+	Slots      []ScheduleSlotView
+	CanAnswer  bool
+}
+
+type ScheduleSlotView struct {
+	Index   int
+	AtLabel string
+	Venue   string
+	ClubID  string
 }
 
 type LeagueView struct {
 	BaseView
-	League          model.League
-	Players         []model.User
-	Standings       []StandingEntry
-	Matches         []MatchView
-	PendingOnly     bool
-	PlayersPanel    LeaguePlayersPanelView
-	PlayerSearch    PlayerSearchView
-	SetsRange       []int
-	IsAdmin         bool
-	IsPlayer        bool
-	PendingJoin     bool
-	Admins          []LeagueAdminView
-	AdminCandidates []model.User
-	JoinRequests    []LeagueJoinRequestView
-	Page            int
-	TotalPages      int
-	Pages           []int
-	HasPrev         bool
-	HasNext         bool
-	PrevPage        int
-	NextPage        int
+	League              model.League
+	Players             []model.User
+	Standings           []StandingEntry
+	PairStandings       []StandingEntry
+	Divisions           []DivisionView
+	Unassigned          []model.User
+	MatchPlayers        []model.User
+	Teams               []TeamView
+	TeamCandidates      []model.User
+	Fixtures            []FixtureView
+	TeamStandings       []TeamStandingEntry
+	Handicaps           []HandicapView
+	Roster              []RosterView
+	Withdrawals         []WithdrawalView
+	PolicyText          string
+	Invites             []InviteView
+	VisibilityText      string
+	CapacityText        string
+	IsOwner             bool
+	IsSuperAdmin        bool
+	Can                 LeaguePermissions
+	PermissionRoles     []PermissionRoleView
+	PermissionRows      []PermissionRowView
+	PendingOwner        model.User
+	OwnershipCandidates []model.User
+	Waitlist            []WaitlistView
+	WaitlistEntry       WaitlistView
+	Schedule            []MatchView
+	ScheduleOpponents   []model.User
+	CanSchedule         bool
+	SlotsRange          []int
+	CalendarURL         string
+	Club                model.Club
+	Clubs               []model.Club
+	TieBreakers         []string
+	SetFormat           SetFormat
+	Scoring             model.ScoringRules
+	AutoConfirmText     string
+	Matches             []MatchView
+	PendingOnly         bool
+	PlayersPanel        LeaguePlayersPanelView
+	PlayerSearch        PlayerSearchView
+	SetsRange           []int
+	IsAdmin             bool
+	IsPlayer            bool
+	PendingJoin         bool
+	Admins              []LeagueAdminView
+	AdminCandidates     []model.User
+	JoinRequests        []LeagueJoinRequestView
+	Disputes            []MatchView
+	Seasons             []SeasonLinkView
+	Frozen              bool
+	FrozenAt            string
+	Awards              []AwardView
+	HasNextSeason       bool
+	NextSeasonName      string
+	Page                int
+	TotalPages          int
+	Pages               []int
+	HasPrev             bool
+	HasNext             bool
+	PrevPage            int
+	NextPage            int
+}
+
+type LeagueFormView struct {
+	BaseView
+	TieBreakPresets []tieBreakPreset
+	TieBreakPreset  string
+	Clubs           []model.Club
+}
+
+type LeaguePermissions struct {
+	ManagePlayers   bool
+	ManageAdmins    bool
+	EditResults     bool
+	ResolveDisputes bool
+	EndLeague       bool
+	EditSettings    bool
+}
+
+type PermissionRoleView struct {
+	Role  model.LeagueAdminRole
+	Label string
+}
+
+type PermissionRowView struct {
+	Capability model.Capability
+	Label      string
+	Cells      []PermissionCellView
+}
+
+type PermissionCellView struct {
+	Role    model.LeagueAdminRole
+	Granted bool
+}
+
+type LeagueSettingsForm struct {
+	Name         string
+	Description  string
+	Location     string
+	ClubID       string
+	StartDate    string
+	EndDate      string
+	SetsPerMatch int
+	PointsPerSet int
+}
+
+type LeagueEditView struct {
+	BaseView
+	League       model.League
+	Form         LeagueSettingsForm
+	Errors       []string
+	FormatLocked bool
+	IsOwner      bool
+	Clubs        []model.Club
 }
 
 type LeagueSearchView struct {
@@ -79,13 +237,92 @@ type LeagueSearchResultView struct {
 }
 
 type MatchView struct {
-	Match      model.Match
-	PlayerA    model.User
-	PlayerB    model.User
-	ScoreLine  string
-	CanConfirm bool
-	CanReject  bool
-	StatusText string
+	Match         model.Match
+	PlayerA       model.User
+	PlayerB       model.User
+	ScoreLine     string
+	HeadStartText string
+	CanConfirm    bool
+	CanReject     bool
+	StatusText    string
+}
+
+type DivisionView struct {
+	Division      model.Division
+	Players       []model.User
+	Standings     []StandingEntry
+	PairStandings []StandingEntry
+}
+
+type AwardView struct {
+	Label      string
+	PlayerName string
+	Note       string
+}
+
+type SeasonLinkView struct {
+	LeagueID string
+	Label    string
+	Current  bool
+}
+
+type MatchPageView struct {
+	BaseView
+	League           model.League
+	Match            MatchView
+	SetFormat        SetFormat
+	Revisions        []MatchRevisionView
+	Dispute          *MatchDisputeView
+	CanEdit          bool
+	CanDispute       bool
+	CanAnswerDispute bool
+	CanResolve       bool
+	CanSchedule      bool
+	Proposal         *ScheduleProposalView
+	ScheduleLabel    string
+	ConfirmDeadline  string
+	AutoConfirmText  string
+	CurrentResult    MatchResultFieldsView
+	EmptyResult      MatchResultFieldsView
+	Reason           string
+	EditErrors       []string
+	DisputeErrors    []string
+	ResolveErrors    []string
+	ScheduleErrors   []string
+	ReportErrors     []string
+	SlotsRange       []int
+	Clubs            []model.Club
+	Booking          *MatchBookingView
+}
+
+type MatchDisputeView struct {
+	Dispute        model.MatchDispute
+	RaisedBy       model.User
+	ScoreLine      string
+	CreatedAtLabel string
+}
+
+type MatchRevisionView struct {
+	Revision       model.MatchRevision
+	ChangedBy      string
+	ActionText     string
+	ScoreLine      string
+	StatusText     string
+	CreatedAtLabel string
+}
+
+type MatchResultFieldsView struct {
+	PlayerA      model.User
+	PlayerB      model.User
+	Outcome      model.MatchOutcome
+	ConcededSide string
+	Sets         []SetInputView
+}
+
+type SetInputView struct {
+	Number int
+	A      string
+	B      string
 }
 
 type RecentActivityItem struct {
@@ -132,7 +369,8 @@ type FriendlyDashboardView struct {
 }
 
 type FriendlySearchResult struct {
-	User model.User
+	User   model.User
+	Rating rating.Rating
 }
 
 type FriendlySearchView struct {
@@ -148,12 +386,15 @@ type FriendlySearchInputView struct {
 }
 
 type FriendlyFormView struct {
-	LeagueUsers []model.User
-	Search      FriendlySearchView
-	SearchInput FriendlySearchInputView
-	Selected    *model.User
-	PlayedAt    string
-	Error       string
+	LeagueUsers  []model.User
+	Search       FriendlySearchView
+	SearchInput  FriendlySearchInputView
+	Selected     *model.User
+	PlayedAt     string
+	BestOf       int
+	PointsPerSet int
+	Error        string
+	SetErrors    []string
 }
 
 type AuthView struct {
@@ -222,13 +463,20 @@ type MatchesListView struct {
 }
 
 type StandingEntry struct {
-	Player     model.User
-	Points     int
-	Matches    int
-	SetsWon    int
-	SetsLost   int
-	PointsWon  int
-	PointsLost int
+	Position     int
+	Player       model.User
+	Points       int
+	Matches      int
+	Wins         int
+	Losses       int
+	SetsWon      int
+	SetsLost     int
+	PointsWon    int
+	PointsLost   int
+	TieBreak     model.TieBreakRule
+	TieBreakNote string
+	Withdrawn    bool
+	Rating       rating.Rating
 }
 
 type LeaguePlayersPanelView struct {
@@ -240,6 +488,7 @@ type LeaguePlayersPanelView struct {
 type PlayerSearchResult struct {
 	User     model.User
 	InLeague bool
+	Rating   rating.Rating
 }
 
 type PlayerSearchView struct {
@@ -261,3 +510,190 @@ type LeagueJoinRequestView struct {
 	Request model.LeagueJoinRequest
 	User    model.User
 }
+
+type TeamView struct {
+	Team      model.Team
+	Captain   model.User
+	Players   []model.User
+	CanManage bool
+}
+
+type TeamStandingEntry struct {
+	Position    int
+	Team        model.Team
+	Points      int
+	Fixtures    int
+	Wins        int
+	Draws       int
+	Losses      int
+	RubbersWon  int
+	RubbersLost int
+}
+
+type FixtureView struct {
+	Fixture     model.Fixture
+	Home        model.Team
+	Away        model.Team
+	PlayAtLabel string
+	Result      fixtureResult
+	StatusText  string
+}
+
+type RubberView struct {
+	Number    int
+	Match     MatchView
+	CanReport bool
+	Fields    MatchResultFieldsView
+}
+
+type FixturePageView struct {
+	BaseView
+	League        model.League
+	Fixture       FixtureView
+	Locked        bool
+	Sides         []FixtureSideView
+	Rubbers       []RubberView
+	OrderErrors   []string
+	RubberErrors  []string
+	RubberErrorID string
+}
+
+type FixtureSideView struct {
+	Label     string
+	Team      model.Team
+	Players   []model.User
+	Order     []model.User
+	Slots     []FixtureSlotView
+	Submitted bool
+	CanOrder  bool
+}
+
+type FixtureSlotView struct {
+	Number     int
+	SelectedID string
+}
+
+type HandicapView struct {
+	Player model.User
+	Value  int
+	Custom bool
+}
+
+type RosterView struct {
+	Player    model.User
+	Suspended bool
+	IsOwner   bool
+}
+
+type WithdrawalView struct {
+	Player     model.User
+	KindText   string
+	PolicyText string
+	Reason     string
+	DateLabel  string
+}
+
+type WaitlistView struct {
+	Player        model.User
+	Position      int
+	Offered       bool
+	DeadlineLabel string
+}
+
+type InviteView struct {
+	Invite       model.LeagueInvite
+	URL          string
+	Active       bool
+	ExpiresLabel string
+}
+
+type InvitePageView struct {
+	BaseView
+	Token         string
+	Valid         bool
+	League        model.League
+	ExpiresLabel  string
+	AlreadyMember bool
+}
+
+type ClubForm struct {
+	Name      string
+	Address   string
+	Latitude  string
+	Longitude string
+}
+
+type ClubsView struct {
+	BaseView
+	Clubs  []ClubListItem
+	Form   ClubForm
+	Errors []string
+}
+
+type ClubListItem struct {
+	Club    model.Club
+	Leagues int
+}
+
+type ClubView struct {
+	BaseView
+	Club            model.Club
+	Form            ClubForm
+	Errors          []string
+	MapURL          string
+	Hours           []OpeningHoursView
+	Admins          []model.User
+	AdminCandidates []model.User
+	Leagues         []model.League
+	Results         []RecentActivityItem
+	Bookings        BookingDayView
+	CanManage       bool
+}
+
+type BookingDayView struct {
+	Day      string
+	DayLabel string
+	PrevDay  string
+	NextDay  string
+	Courts   []model.Court
+	Rows     []BookingRowView
+	Error    string
+}
+
+type BookingRowView struct {
+	Label string
+	Cells []BookingCellView
+}
+
+type BookingCellView struct {
+	CourtID   string
+	Start     string
+	Free      bool
+	Past      bool
+	BookingID string
+	BookedBy  model.User
+	MatchID   string
+	CanCancel bool
+}
+
+type MatchBookingView struct {
+	Club      model.Club
+	BookingID string
+	Label     string
+	CanCancel bool
+	Slots     []BookingSlotView
+}
+
+type BookingSlotView struct {
+	CourtID string
+	Start   string
+	Label   string
+}
+
+type OpeningHoursView struct {
+	Weekday int
+	Label   string
+	Opens   string
+	Closes  string
+	Closed  bool
+}
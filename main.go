@@ -1,16 +1,21 @@
 package main
 
 import (
+	"context"
 	"embed"
+	"encoding/json"
 	"io/fs"
 	"log"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
+	"sqoush-app/internal/jobs"
 	"sqoush-app/internal/store"
 	"sqoush-app/internal/web"
 
+	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/awslabs/aws-lambda-go-api-proxy/httpadapter"
 	"github.com/go-chi/chi/v5"
@@ -29,6 +34,8 @@ func main() {
 		_ = godotenv.Load(".env", ".env.local")
 	}
 	var appStore store.Store
+	var jobLocker jobs.Locker
+	var jobRuns jobs.RunLog
 	if dsn := strings.TrimSpace(os.Getenv("POSTGRES_DSN")); dsn != "" {
 		pgStore, err := store.NewPostgresStore(dsn, store.PostgresOptions{
 			MigrationsDir: os.Getenv("POSTGRES_MIGRATIONS_DIR"),
@@ -37,10 +44,13 @@ func main() {
 			log.Fatalf("postgres store: %v", err)
 		}
 		appStore = pgStore
+		jobLocker = pgStore
+		jobRuns = pgStore
 	} else {
 		appStore = store.NewMemoryStore()
 	}
-	server := web.NewServer(appStore, templates)
+	server := web.NewServer(appStore, templates, nil)
+	runner := jobs.NewRunner(jobLocker, jobRuns, jobs.Default(appStore, server.FreezeStandings)...)
 	staticFS, err := fs.Sub(content, "static")
 	if err != nil {
 		log.Fatalf("static fs: %v", err)
@@ -58,8 +68,28 @@ func main() {
 	if os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != "" {
 		log.Println("Uruchamianie w trybie Lambda...")
 		adapter := httpadapter.New(r)
-		lambda.Start(adapter.ProxyWithContext)
+		lambda.Start(func(ctx context.Context, event json.RawMessage) (any, error) {
+			// EventBridge schedule rules invoke the same function as API Gateway.
+			// The rule input may list {"detail": {"jobs": [...]}}; otherwise every due job runs.
+			var scheduled struct {
+				Source     string `json:"source"`
+				DetailType string `json:"detail-type"`
+				Detail     struct {
+					Jobs []string `json:"jobs"`
+				} `json:"detail"`
+			}
+			_ = json.Unmarshal(event, &scheduled)
+			if scheduled.Source == "aws.events" && scheduled.DetailType == "Scheduled Event" {
+				return nil, runner.RunNamed(ctx, time.Now(), scheduled.Detail.Jobs...)
+			}
+			var req events.APIGatewayProxyRequest
+			if err := json.Unmarshal(event, &req); err != nil {
+				return nil, err
+			}
+			return adapter.ProxyWithContext(ctx, req)
+		})
 	} else {
+		go runner.Start(context.Background(), time.Minute)
 		port := strings.TrimSpace(os.Getenv("PORT"))
 		if port == "" {
 			port = "8080"
@@ -0,0 +1 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS tie_breakers JSONB;
@@ -0,0 +1 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS points_per_set INTEGER NOT NULL DEFAULT 11;
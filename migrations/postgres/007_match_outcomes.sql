@@ -0,0 +1,7 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS scoring JSONB;
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS outcome TEXT NOT NULL DEFAULT 'normal';
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS conceded_by TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE friendly_matches ADD COLUMN IF NOT EXISTS outcome TEXT NOT NULL DEFAULT 'normal';
+ALTER TABLE friendly_matches ADD COLUMN IF NOT EXISTS conceded_by TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS match_revisions (
+  id TEXT PRIMARY KEY,
+  match_id TEXT REFERENCES matches(id),
+  sets_json JSONB,
+  outcome TEXT NOT NULL DEFAULT 'normal',
+  conceded_by TEXT NOT NULL DEFAULT '',
+  status TEXT NOT NULL,
+  changed_by TEXT REFERENCES users(id),
+  reason TEXT NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_match_revisions_match_id ON match_revisions(match_id, created_at);
@@ -0,0 +1,3 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS dispute JSONB;
+
+ALTER TABLE match_revisions ADD COLUMN IF NOT EXISTS action TEXT NOT NULL DEFAULT 'corrected';
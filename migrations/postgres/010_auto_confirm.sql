@@ -0,0 +1,10 @@
+-- Existing leagues keep waiting for confirmation; owners opt in per league.
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS confirmation_hours INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS auto_confirm_action TEXT NOT NULL DEFAULT 'confirm';
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS auto_confirmed BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE friendly_matches ADD COLUMN IF NOT EXISTS auto_confirmed BOOLEAN NOT NULL DEFAULT false;
+
+-- System actions are recorded without a user.
+ALTER TABLE match_revisions DROP CONSTRAINT IF EXISTS match_revisions_changed_by_fkey;
+ALTER TABLE match_revisions ALTER COLUMN changed_by DROP NOT NULL;
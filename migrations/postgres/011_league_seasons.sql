@@ -0,0 +1,10 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS season INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS previous_season_id TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_leagues_previous_season_id ON leagues(previous_season_id);
+
+CREATE TABLE IF NOT EXISTS league_standings_snapshots (
+  league_id TEXT PRIMARY KEY REFERENCES leagues(id),
+  entries JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
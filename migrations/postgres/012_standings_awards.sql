@@ -0,0 +1 @@
+ALTER TABLE league_standings_snapshots ADD COLUMN IF NOT EXISTS awards JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS divisions JSONB NOT NULL DEFAULT '[]'::jsonb;
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS promotion_count INTEGER NOT NULL DEFAULT 0;
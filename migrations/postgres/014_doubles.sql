@@ -0,0 +1,7 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS doubles BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS partner_a_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS partner_b_id TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE friendly_matches ADD COLUMN IF NOT EXISTS partner_a_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE friendly_matches ADD COLUMN IF NOT EXISTS partner_b_id TEXT NOT NULL DEFAULT '';
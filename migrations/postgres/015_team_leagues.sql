@@ -0,0 +1,19 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS competition TEXT NOT NULL DEFAULT 'individual';
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS rubbers_per_fixture INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS teams JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+CREATE TABLE IF NOT EXISTS fixtures (
+  id TEXT PRIMARY KEY,
+  league_id TEXT NOT NULL REFERENCES leagues(id),
+  home_team_id TEXT NOT NULL,
+  away_team_id TEXT NOT NULL,
+  play_at TIMESTAMPTZ NOT NULL,
+  home_order JSONB,
+  away_order JSONB,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fixtures_league_id ON fixtures(league_id);
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS fixture_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS rubber INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS handicap BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS handicaps JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS head_start_a INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS head_start_b INTEGER NOT NULL DEFAULT 0;
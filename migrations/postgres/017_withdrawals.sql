@@ -0,0 +1,2 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS withdrawal_policy TEXT NOT NULL DEFAULT 'keep';
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS withdrawals JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,15 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS visibility TEXT NOT NULL DEFAULT 'public';
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS join_code TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS league_invites (
+  id TEXT PRIMARY KEY,
+  league_id TEXT NOT NULL REFERENCES leagues(id),
+  token TEXT NOT NULL UNIQUE,
+  created_by TEXT NOT NULL,
+  expires_at TIMESTAMPTZ NOT NULL,
+  revoked BOOLEAN NOT NULL DEFAULT false,
+  uses INTEGER NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_league_invites_league_id ON league_invites(league_id);
@@ -0,0 +1,2 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS max_players INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS waitlist JSONB NOT NULL DEFAULT '[]'::jsonb;
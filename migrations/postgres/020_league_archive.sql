@@ -0,0 +1 @@
+ALTER TABLE leagues ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
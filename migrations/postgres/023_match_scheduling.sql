@@ -0,0 +1,5 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMPTZ;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS venue TEXT NOT NULL DEFAULT '';
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS proposal JSONB;
+
+CREATE INDEX IF NOT EXISTS idx_matches_scheduled_at ON matches(scheduled_at);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS court_bookings (
+  id TEXT PRIMARY KEY,
+  club_id TEXT NOT NULL REFERENCES clubs(id),
+  court_id TEXT NOT NULL,
+  user_id TEXT NOT NULL REFERENCES users(id),
+  match_id TEXT NOT NULL DEFAULT '',
+  start_at TIMESTAMPTZ NOT NULL,
+  end_at TIMESTAMPTZ NOT NULL,
+  cancelled_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_court_bookings_club_start ON court_bookings(club_id, start_at);
+CREATE INDEX IF NOT EXISTS idx_court_bookings_match_id ON court_bookings(match_id) WHERE match_id <> '';
+
+-- Slots on a court start at fixed times, so two live bookings of the same
+-- slot are a double booking.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_court_bookings_live_slot ON court_bookings(court_id, start_at) WHERE cancelled_at IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS reported_at TIMESTAMPTZ;
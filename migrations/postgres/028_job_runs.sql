@@ -0,0 +1,6 @@
+-- Background jobs record their last start here, so every app instance sees
+-- when a job is next due.
+CREATE TABLE IF NOT EXISTS job_runs (
+  name TEXT PRIMARY KEY,
+  last_run_at TIMESTAMPTZ NOT NULL
+);
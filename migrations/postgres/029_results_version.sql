@@ -0,0 +1,19 @@
+-- Every write to a match table bumps the sequence, so the app can tell when
+-- ratings computed from the results have gone stale.
+CREATE SEQUENCE IF NOT EXISTS results_version;
+
+CREATE OR REPLACE FUNCTION bump_results_version() RETURNS trigger AS $$
+BEGIN
+  PERFORM nextval('results_version');
+  RETURN NULL;
+END $$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS matches_results_version ON matches;
+CREATE TRIGGER matches_results_version
+  AFTER INSERT OR UPDATE OR DELETE ON matches
+  FOR EACH STATEMENT EXECUTE FUNCTION bump_results_version();
+
+DROP TRIGGER IF EXISTS friendly_matches_results_version ON friendly_matches;
+CREATE TRIGGER friendly_matches_results_version
+  AFTER INSERT OR UPDATE OR DELETE ON friendly_matches
+  FOR EACH STATEMENT EXECUTE FUNCTION bump_results_version();